@@ -1,20 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
 	"github.com/thoas/go-funk"
-	"gopkg.in/yaml.v3"
 )
 
 var elasticProfiles []string
@@ -82,11 +77,12 @@ func getAgentProfilesCommand() *cobra.Command {
 
 func getAgentProfileCommand() *cobra.Command {
 	getElasticAgentProfileCmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET a specific elastic agent profile present in GoCD [https://api.gocd.org/current/#get-an-elastic-agent-profile]",
-		Example: "gocd-cli elastic-agent-profile get sample_kubernetes",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get",
+		Short:             "Command to GET a specific elastic agent profile present in GoCD [https://api.gocd.org/current/#get-an-elastic-agent-profile]",
+		Example:           "gocd-cli elastic-agent-profile get sample_kubernetes",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceElasticAgentProfile),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetElasticAgentProfile(args[0])
@@ -152,17 +148,8 @@ func updateAgentProfileCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &commonCfg); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &commonCfg); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &commonCfg); err != nil {
+				return err
 			}
 
 			elasticAgentProfileFetched, err := client.GetElasticAgentProfile(commonCfg.ID)
@@ -176,22 +163,19 @@ func updateAgentProfileCommand() *cobra.Command {
 				}
 			}
 
-			if len(commonCfg.ETAG) == 0 {
-				commonCfg.ETAG = elasticAgentProfileFetched.ETAG
+			if commonCfg, err = rebaseResource(elasticAgentProfileFetched, commonCfg); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "elastic-agent-profile", elasticAgentProfileFetched.ID)
 
-			existing, err := diffCfg.String(elasticAgentProfileFetched)
-			if err != nil {
+			if err = cliCfg.CheckDiffAndAllow(elasticAgentProfileFetched, commonCfg, object.String()); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
-				return err
-			}
-
-			response, err := client.UpdateElasticAgentProfile(commonCfg)
+			response, err := updateResource(elasticAgentProfileFetched, commonCfg, func() (gocd.CommonConfig, error) {
+				return client.GetElasticAgentProfile(commonCfg.ID)
+			}, client.UpdateElasticAgentProfile)
 			if err != nil {
 				return err
 			}
@@ -208,6 +192,8 @@ func updateAgentProfileCommand() *cobra.Command {
 	updateElasticAgentProfileCmd.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a elastic agent profile by this name doesn't already exist, run create")
 
+	setUpdateMergeHelp(updateElasticAgentProfileCmd)
+
 	return updateElasticAgentProfileCmd
 }
 
@@ -217,23 +203,15 @@ func deleteAgentProfileCommand() *cobra.Command {
 		Short: "Command to DELETE a specific elastic agent profile present in GoCD [https://api.gocd.org/current/#delete-an-elastic-agent-profile]",
 		Example: `gocd-cli elastic-agent-profile delete sample_kubernetes
 gocd-cli elastic-agent-profile delete sample_kubernetes -y`,
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceElasticAgentProfile),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			profileName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete elastic-agent-profile '%s' [y/n]", profileName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteElasticAgentProfile(profileName); err != nil {
@@ -348,17 +326,8 @@ func createAgentProfiles(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &commonCfg); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &commonCfg); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &commonCfg); err != nil {
+		return err
 	}
 
 	response, err := client.CreateElasticAgentProfile(commonCfg)
@@ -1,19 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
 	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
 	"github.com/thoas/go-funk"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -174,17 +170,8 @@ func updateAgentCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &agent); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &agent); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &agent); err != nil {
+				return err
 			}
 
 			agentFetched, err := client.GetAgent(agent.ID)
@@ -194,12 +181,7 @@ func updateAgentCommand() *cobra.Command {
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "agent", agentFetched.ID)
 
-			existing, err := diffCfg.String(agentFetched)
-			if err != nil {
-				return err
-			}
-
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
+			if err = cliCfg.CheckDiffAndAllow(agentFetched, agent, object.String()); err != nil {
 				return err
 			}
 
@@ -227,6 +209,10 @@ func disableAgentCommand() *cobra.Command {
 				return err
 			}
 
+			if wait && cliCfg.DryRun {
+				return errDryRunWithheld
+			}
+
 			startTime := time.Now()
 
 			if wait {
@@ -299,25 +285,14 @@ gocd-cli agents delete --id 938d1935-bdca-4728-83d5-e96cbf0a4f8b`,
 				}
 
 				if len(agentID) == 0 {
-					cliLogger.Errorf("failed to delete agent '%s', as it does not exists in GoCD", agentName)
-
-					return nil
+					return &errors.NotFoundError{Message: fmt.Sprintf("failed to delete agent '%s', as it does not exists in GoCD", agentName)}
 				}
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete agent '%s' [y/n]", agentID)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			response, err := client.DeleteAgent(agentID)
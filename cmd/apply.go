@@ -0,0 +1,575 @@
+package cmd
+
+import (
+	goerrors "errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-cli/pkg/structdiff"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	gocderrors "github.com/nikhilsbhat/gocd-sdk-go/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	siteURLName      = "site-url"
+	mailServerName   = "mail-server"
+	jobTimeoutName   = "job-timeout"
+	backupConfigName = "backup-config"
+	jobTimeoutKey    = "default_job_timeout"
+)
+
+var manifestFiles []string
+
+// manifestOmittedFields are the fields of the resources that are managed by GoCD server, they are neither saved in the manifests nor compared.
+var manifestOmittedFields = map[string][]string{
+	manifest.KindConfigRepo:    {"config_repo_parse_info", "environments", "groups"},
+	manifest.KindPipelineGroup: {"pipelines"},
+	manifest.KindPipeline:      {"origin"},
+	manifest.KindEnvironment:   {"origins"},
+}
+
+// manifestChange is the change identified for a resource declared in the manifests.
+type manifestChange struct {
+	document manifest.Document
+	name     string
+	existing interface{}
+	desired  interface{}
+	apply    func() error
+}
+
+// manifestPlanner decodes the document, fetches the resource it declares from GoCD server and returns the change to be applied.
+type manifestPlanner func(document manifest.Document) (*manifestChange, error)
+
+// manifestPlanners holds the manifestPlanner of every kind supported by 'apply', add an entry here to support a new kind.
+var manifestPlanners = map[string]manifestPlanner{
+	manifest.KindSiteURL: func(document manifest.Document) (*manifestChange, error) {
+		var siteURL gocd.SiteURLConfig
+		if err := document.Decode(&siteURL); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetSiteURL()
+
+		return newSingletonChange(document, siteURLName, fetched, siteURL, err, func() error {
+			_, err := client.CreateOrUpdateSiteURL(siteURL)
+
+			return err
+		})
+	},
+	manifest.KindMailServer: func(document manifest.Document) (*manifestChange, error) {
+		var mailServer gocd.MailServerConfig
+		if err := document.Decode(&mailServer); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetMailServerConfig()
+
+		return newSingletonChange(document, mailServerName, fetched, mailServer, err, func() error {
+			_, err := client.CreateOrUpdateMailServerConfig(mailServer)
+
+			return err
+		})
+	},
+	manifest.KindJobTimeout: func(document manifest.Document) (*manifestChange, error) {
+		var jobTimeout map[string]string
+		if err := document.Decode(&jobTimeout); err != nil {
+			return nil, err
+		}
+
+		timeout, err := strconv.Atoi(jobTimeout[jobTimeoutKey])
+		if err != nil {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("'%s' of %s should be the timeout in minutes: %v", jobTimeoutKey, document.Source, err)}
+		}
+
+		fetched, err := client.GetDefaultJobTimeout()
+
+		return newSingletonChange(document, jobTimeoutName, fetched, jobTimeout, err, func() error {
+			return client.UpdateDefaultJobTimeout(timeout)
+		})
+	},
+	manifest.KindBackupConfig: func(document manifest.Document) (*manifestChange, error) {
+		var backupConfig gocd.BackupConfig
+		if err := document.Decode(&backupConfig); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetBackupConfig()
+
+		return newSingletonChange(document, backupConfigName, fetched, backupConfig, err, func() error {
+			return client.CreateOrUpdateBackupConfig(backupConfig)
+		})
+	},
+	manifest.KindPluginSettings: func(document manifest.Document) (*manifestChange, error) {
+		var setting gocd.PluginSettings
+		if err := document.Decode(&setting); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetPluginSettings(setting.ID)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, setting.ID, nil, setting, func() error {
+				_, err := client.CreatePluginSettings(setting)
+
+				return err
+			}), nil
+		}
+
+		if setting, err = rebaseResource(fetched, setting); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, setting.ID, fetched, setting, func() error {
+			_, err := updateResource(fetched, setting, func() (gocd.PluginSettings, error) {
+				return client.GetPluginSettings(setting.ID)
+			}, client.UpdatePluginSettings)
+
+			return err
+		}), nil
+	},
+	manifest.KindAuthConfig: func(document manifest.Document) (*manifestChange, error) {
+		var authConfig gocd.CommonConfig
+		if err := document.Decode(&authConfig); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetAuthConfig(authConfig.ID)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, authConfig.ID, nil, authConfig, func() error {
+				_, err := client.CreateAuthConfig(authConfig)
+
+				return err
+			}), nil
+		}
+
+		if authConfig, err = rebaseResource(fetched, authConfig); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, authConfig.ID, fetched, authConfig, func() error {
+			_, err := updateResource(fetched, authConfig, func() (gocd.CommonConfig, error) {
+				return client.GetAuthConfig(authConfig.ID)
+			}, client.UpdateAuthConfig)
+
+			return err
+		}), nil
+	},
+	manifest.KindRole: func(document manifest.Document) (*manifestChange, error) {
+		var role gocd.Role
+		if err := document.Decode(&role); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetRole(role.Name)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, role.Name, nil, role, func() error {
+				_, err := client.CreateRole(role)
+
+				return err
+			}), nil
+		}
+
+		if role, err = rebaseResource(fetched, role); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, role.Name, fetched, role, func() error {
+			_, err := updateResource(fetched, role, func() (gocd.Role, error) {
+				return client.GetRole(role.Name)
+			}, client.UpdateRole)
+
+			return err
+		}), nil
+	},
+	manifest.KindClusterProfile: func(document manifest.Document) (*manifestChange, error) {
+		var clusterProfile gocd.CommonConfig
+		if err := document.Decode(&clusterProfile); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetClusterProfile(clusterProfile.ID)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, clusterProfile.ID, nil, clusterProfile, func() error {
+				_, err := client.CreateClusterProfile(clusterProfile)
+
+				return err
+			}), nil
+		}
+
+		if clusterProfile, err = rebaseResource(fetched, clusterProfile); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, clusterProfile.ID, fetched, clusterProfile, func() error {
+			_, err := updateResource(fetched, clusterProfile, func() (gocd.CommonConfig, error) {
+				return client.GetClusterProfile(clusterProfile.ID)
+			}, client.UpdateClusterProfile)
+
+			return err
+		}), nil
+	},
+	manifest.KindElasticAgentProfile: func(document manifest.Document) (*manifestChange, error) {
+		var elasticAgentProfile gocd.CommonConfig
+		if err := document.Decode(&elasticAgentProfile); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetElasticAgentProfile(elasticAgentProfile.ID)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, elasticAgentProfile.ID, nil, elasticAgentProfile, func() error {
+				_, err := client.CreateElasticAgentProfile(elasticAgentProfile)
+
+				return err
+			}), nil
+		}
+
+		if elasticAgentProfile, err = rebaseResource(fetched, elasticAgentProfile); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, elasticAgentProfile.ID, fetched, elasticAgentProfile, func() error {
+			_, err := updateResource(fetched, elasticAgentProfile, func() (gocd.CommonConfig, error) {
+				return client.GetElasticAgentProfile(elasticAgentProfile.ID)
+			}, client.UpdateElasticAgentProfile)
+
+			return err
+		}), nil
+	},
+	manifest.KindArtifactStore: func(document manifest.Document) (*manifestChange, error) {
+		var artifactStore gocd.CommonConfig
+		if err := document.Decode(&artifactStore); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetArtifactStore(getArtifactStoreID(artifactStore))
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, getArtifactStoreID(artifactStore), nil, artifactStore, func() error {
+				_, err := client.CreateArtifactStore(artifactStore)
+
+				return err
+			}), nil
+		}
+
+		if artifactStore, err = rebaseResource(fetched, artifactStore); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, getArtifactStoreID(artifactStore), fetched, artifactStore, func() error {
+			_, err := updateResource(fetched, artifactStore, func() (gocd.CommonConfig, error) {
+				return client.GetArtifactStore(getArtifactStoreID(artifactStore))
+			}, client.UpdateArtifactStore)
+
+			return err
+		}), nil
+	},
+	manifest.KindConfigRepo: func(document manifest.Document) (*manifestChange, error) {
+		var configRepo gocd.ConfigRepo
+		if err := document.Decode(&configRepo); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetConfigRepo(configRepo.ID)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, configRepo.ID, nil, configRepo, func() error {
+				return client.CreateConfigRepo(configRepo)
+			}), nil
+		}
+
+		if configRepo, err = rebaseResource(fetched, configRepo); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, configRepo.ID, fetched, configRepo, func() error {
+			_, err := updateResource(fetched, configRepo, func() (gocd.ConfigRepo, error) {
+				return client.GetConfigRepo(configRepo.ID)
+			}, client.UpdateConfigRepo)
+
+			return err
+		}), nil
+	},
+	manifest.KindPipelineGroup: func(document manifest.Document) (*manifestChange, error) {
+		var pipelineGroup gocd.PipelineGroup
+		if err := document.Decode(&pipelineGroup); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetPipelineGroup(pipelineGroup.Name)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, pipelineGroup.Name, nil, pipelineGroup, func() error {
+				return client.CreatePipelineGroup(pipelineGroup)
+			}), nil
+		}
+
+		if pipelineGroup, err = rebaseResource(fetched, pipelineGroup); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, pipelineGroup.Name, fetched, pipelineGroup, func() error {
+			_, err := updateResource(fetched, pipelineGroup, func() (gocd.PipelineGroup, error) {
+				return client.GetPipelineGroup(pipelineGroup.Name)
+			}, client.UpdatePipelineGroup)
+
+			return err
+		}), nil
+	},
+	manifest.KindPipeline: func(document manifest.Document) (*manifestChange, error) {
+		var pipelineConfig gocd.PipelineConfig
+		if err := document.Decode(&pipelineConfig); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetPipelineConfig(pipelineConfig.Name)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, pipelineConfig.Name, nil, pipelineConfig, func() error {
+				_, err := client.CreatePipeline(pipelineConfig)
+
+				return err
+			}), nil
+		}
+
+		if pipelineConfig, err = rebaseResource(fetched, pipelineConfig); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, pipelineConfig.Name, fetched, pipelineConfig, func() error {
+			_, err := updateResource(fetched, pipelineConfig, func() (gocd.PipelineConfig, error) {
+				return client.GetPipelineConfig(pipelineConfig.Name)
+			}, client.UpdatePipelineConfig)
+
+			return err
+		}), nil
+	},
+	manifest.KindEnvironment: func(document manifest.Document) (*manifestChange, error) {
+		var environment gocd.Environment
+		if err := document.Decode(&environment); err != nil {
+			return nil, err
+		}
+
+		fetched, err := client.GetEnvironment(environment.Name)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+
+			return newManifestChange(document, environment.Name, nil, environment, func() error {
+				return client.CreateEnvironment(environment)
+			}), nil
+		}
+
+		if environment, err = rebaseResource(fetched, environment); err != nil {
+			return nil, err
+		}
+
+		return newManifestChange(document, environment.Name, fetched, environment, func() error {
+			_, err := updateResource(fetched, environment, func() (gocd.Environment, error) {
+				return client.GetEnvironment(environment.Name)
+			}, client.UpdateEnvironment)
+
+			return err
+		}), nil
+	},
+}
+
+func registerApplyCommand() *cobra.Command {
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Command to CREATE or UPDATE all the resources declared in the manifests, with a single confirmation",
+		Long: fmt.Sprintf(`Command to CREATE or UPDATE all the resources declared in the manifests, with a single confirmation.
+A manifest could hold more than one document separated by '---', every document should have the 'kind' of the resource set
+along with its configuration, the same configuration that the create/update command of the resource accepts via --from-file.
+When a directory is passed, all the YAML and JSON files under it are read.
+
+The resources are applied in the order of their dependencies, kinds supported in that order are: %s.
+The changes to all the resources are shown as a single diff before confirmation.`, strings.Join(manifest.Kinds(), ", ")),
+		Example: `gocd-cli apply -f manifests/
+gocd-cli apply -f cluster-profiles.yaml -f pipelines.yaml --yes`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCLIClient,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			documents, err := manifest.Read(manifestFiles...)
+			if err != nil {
+				return err
+			}
+
+			return applyManifestDocuments(documents)
+		},
+	}
+
+	applyCmd.PersistentFlags().StringSliceVarP(&manifestFiles, "file", "f", nil,
+		"manifest file or directory of manifests to be applied, could be passed multiple times")
+
+	if err := applyCmd.MarkPersistentFlagRequired("file"); err != nil {
+		cliLogger.Fatalf("%v", err)
+	}
+
+	applyCmd.SetUsageTemplate(getUsageTemplate())
+	applyCmd.SilenceUsage = true
+
+	return applyCmd
+}
+
+// applyManifestDocuments identifies the changes to the resources declared in the documents, shows them as a single diff
+// and applies them in the order of the documents once confirmed.
+func applyManifestDocuments(documents []manifest.Document) error {
+	changes := make([]*manifestChange, 0, len(documents))
+	var toCreate, toUpdate int
+
+	for _, document := range documents {
+		change, err := manifestPlanners[document.Kind](document)
+		if err != nil {
+			return err
+		}
+
+		diffIdentified, err := change.diff()
+		if err != nil {
+			return err
+		}
+
+		if len(diffIdentified) == 0 {
+			cliLogger.Debugf("%s '%s' from %s has no changes", document.Kind, change.name, document.Source)
+
+			continue
+		}
+
+		action := "updated"
+		if change.existing == nil {
+			action = "created"
+			toCreate++
+		} else {
+			toUpdate++
+		}
+
+		fmt.Printf("# %s '%s' would be %s (%s)\n%s\n", document.Kind, change.name, action, document.Source, diffIdentified)
+
+		changes = append(changes, change)
+	}
+
+	if len(changes) == 0 {
+		return &errors.NoChangesError{Message: "no changes to the resources in the manifests, nothing to apply, quitting"}
+	}
+
+	fmt.Printf("%d resource(s) would be created and %d updated\n\n", toCreate, toUpdate)
+
+	cliShellReadConfig.ShellMessage = "do you want to apply the above changes [y/n]"
+
+	if err := cliCfg.confirm(); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if err := change.apply(); err != nil {
+			return fmt.Errorf("applying %s '%s' from %s errored with: %w", change.document.Kind, change.name, change.document.Source, err)
+		}
+
+		if err := cliRenderer.Render(fmt.Sprintf("%s %s applied successfully", change.document.Kind, change.name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newManifestChange(document manifest.Document, name string, existing, desired interface{}, apply func() error) *manifestChange {
+	return &manifestChange{document: document, name: name, existing: existing, desired: desired, apply: apply}
+}
+
+// diff returns the diff between the resource on GoCD server and the one declared, it is empty when there are no changes.
+// Both are compared structurally as they would be saved in the manifests, so that neither the fields managed by GoCD server
+// nor the order of the keys and of the lists that are sets show up as changes.
+func (change *manifestChange) diff() (string, error) {
+	var existing interface{}
+
+	if change.existing != nil {
+		existingDocument, err := manifest.NewDocument(change.document.Kind, change.document.Source, change.existing, manifestOmittedFields[change.document.Kind]...)
+		if err != nil {
+			return "", err
+		}
+
+		existing = existingDocument.Object
+	}
+
+	desiredDocument, err := manifest.NewDocument(change.document.Kind, change.document.Source, change.desired, manifestOmittedFields[change.document.Kind]...)
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := structdiff.Diff(existing, desiredDocument.Object)
+	if err != nil {
+		return "", err
+	}
+
+	return structdiff.String(changes), nil
+}
+
+// newSingletonChange returns the change to a resource that GoCD server has only one of, ex: site URL.
+// The resource is considered to be created when it is not set on GoCD server yet.
+func newSingletonChange(document manifest.Document, name string, fetched, desired interface{}, err error, apply func() error) (*manifestChange, error) {
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	var existing interface{}
+	if err == nil && !reflect.ValueOf(fetched).IsZero() {
+		existing = fetched
+	}
+
+	return newManifestChange(document, name, existing, desired, apply), nil
+}
+
+// getArtifactStoreID returns the ID of the artifact store, falling back to its name.
+func getArtifactStoreID(artifactStore gocd.CommonConfig) string {
+	if len(artifactStore.ID) != 0 {
+		return artifactStore.ID
+	}
+
+	return artifactStore.Name
+}
+
+func isNotFound(err error) bool {
+	var notFoundError *gocderrors.NonFoundError
+	if goerrors.As(err, &notFoundError) {
+		return true
+	}
+
+	return errors.ExitCode(err) == errors.ExitCodeNotFound
+}
@@ -1,18 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 func registerArtifactCommand() *cobra.Command {
@@ -48,10 +43,11 @@ GET/CREATE/UPDATE/DELETE GoCD artifact`,
 
 func getArtifactStoreCommand() *cobra.Command {
 	getArtifactStoreCmd := &cobra.Command{
-		Use:     "get-store",
-		Short:   "Command to GET an specific artifact store in GoCD [https://api.gocd.org/current/#get-an-artifact-store]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get-store",
+		Short:             "Command to GET an specific artifact store in GoCD [https://api.gocd.org/current/#get-an-artifact-store]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceArtifactStore),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			response, err := client.GetArtifactStore(args[0])
 			if err != nil {
@@ -167,17 +163,8 @@ func updateArtifactStoreCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &commonCfg); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &commonCfg); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &commonCfg); err != nil {
+				return err
 			}
 
 			artifactStoreFetched, err := client.GetArtifactStore(commonCfg.Name)
@@ -191,22 +178,19 @@ func updateArtifactStoreCommand() *cobra.Command {
 				}
 			}
 
-			if len(commonCfg.ETAG) == 0 {
-				commonCfg.ETAG = artifactStoreFetched.ETAG
+			if commonCfg, err = rebaseResource(artifactStoreFetched, commonCfg); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "artifact-store", artifactStoreFetched.Name)
 
-			existing, err := diffCfg.String(artifactStoreFetched)
-			if err != nil {
+			if err = cliCfg.CheckDiffAndAllow(artifactStoreFetched, commonCfg, object.String()); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
-				return err
-			}
-
-			response, err := client.UpdateArtifactStore(commonCfg)
+			response, err := updateResource(artifactStoreFetched, commonCfg, func() (gocd.CommonConfig, error) {
+				return client.GetArtifactStore(commonCfg.Name)
+			}, client.UpdateArtifactStore)
 			if err != nil {
 				return err
 			}
@@ -222,6 +206,8 @@ func updateArtifactStoreCommand() *cobra.Command {
 	updateArtifactsStoreCmd.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if artifact store config doesn't already exist, run create")
 
+	setUpdateMergeHelp(updateArtifactsStoreCmd)
+
 	return updateArtifactsStoreCmd
 }
 
@@ -238,17 +224,8 @@ func updateArtifactConfigCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &artifactInfo); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &artifactInfo); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &artifactInfo); err != nil {
+				return err
 			}
 
 			artifactConfigFetched, err := client.GetArtifactConfig()
@@ -256,18 +233,17 @@ func updateArtifactConfigCommand() *cobra.Command {
 				return err
 			}
 
-			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "artifact-config", "")
-
-			existing, err := diffCfg.String(artifactConfigFetched)
-			if err != nil {
+			if artifactInfo, err = rebaseResource(artifactConfigFetched, artifactInfo); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
+			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "artifact-config", "")
+
+			if err = cliCfg.CheckDiffAndAllow(artifactConfigFetched, artifactInfo, object.String()); err != nil {
 				return err
 			}
 
-			response, err := client.UpdateArtifactConfig(artifactInfo)
+			response, err := updateResource(artifactConfigFetched, artifactInfo, client.GetArtifactConfig, client.UpdateArtifactConfig)
 			if err != nil {
 				return err
 			}
@@ -280,30 +256,24 @@ func updateArtifactConfigCommand() *cobra.Command {
 		},
 	}
 
+	setUpdateMergeHelp(updateArtifactsConfigCmd)
+
 	return updateArtifactsConfigCmd
 }
 
 func deleteArtifactStoreCommand() *cobra.Command {
 	deleteArtifactsStoreCmd := &cobra.Command{
-		Use:     "delete-store",
-		Short:   "Command to DELETE a specific artifact store present in GoCD [https://api.gocd.org/current/#delete-an-artifact-store]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "delete-store",
+		Short:             "Command to DELETE a specific artifact store present in GoCD [https://api.gocd.org/current/#delete-an-artifact-store]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceArtifactStore),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			storeName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete store '%s' [y/n]", storeName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteArtifactStore(storeName); err != nil {
@@ -350,17 +320,8 @@ func createArtifactStore(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &commonCfg); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &commonCfg); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &commonCfg); err != nil {
+		return err
 	}
 
 	response, err := client.CreateArtifactStore(commonCfg)
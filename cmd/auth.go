@@ -5,24 +5,48 @@ import (
 	"os"
 	"path/filepath"
 
+	ghodssYAML "github.com/ghodss/yaml"
 	"github.com/goccy/go-yaml"
+	"github.com/nikhilsbhat/gocd-cli/pkg/contexts"
 	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
 )
 
 const (
 	goCdCacheDirName       = ".gocd"
 	goCdAuthConfigFileName = "auth_config.%s.yaml"
+	goCdContextsFileName   = "contexts.yaml"
+	goCdPassphraseEnvVar   = "GOCD_CLI_PASSPHRASE"
 )
 
+var (
+	encryptAuthConfig bool
+	revealAuthConfig  bool
+)
+
+type authContext struct {
+	Name       string              `json:"name,omitempty" yaml:"name,omitempty"`
+	Current    bool                `json:"current" yaml:"current"`
+	Server     contexts.Server     `json:"server,omitempty" yaml:"server,omitempty"`
+	Credential contexts.Credential `json:"credential,omitempty" yaml:"credential,omitempty"`
+}
+
 func registerAuthConfigCommand() *cobra.Command {
 	registerAuthConfigCmd := &cobra.Command{
 		Use:   "auth-config",
 		Short: "Command to store/remove the authorization configuration to be used by the cli",
 		Long: `Using the auth config commands, one can cache the authorization configuration onto a file so it can be used by further calls made using this utility.
-Also, the cached authentication configurations can be erased using the same`,
+Also, the cached authentication configurations can be erased using the same.
+
+All authorization configurations are saved as contexts under $HOME/.gocd/contexts.yaml, the context to be used is picked from
+--profile if set, else from the current-context of the contexts file, which can be switched using 'auth-config use-context'.`,
 		Example: `gocd-cli auth-config store --server-url http://localhost:8153/go --username user --password password
 gocd-cli auth-config store --server-url http://localhost:8153/go --username user --password password --profile central
+gocd-cli auth-config use-context central
+gocd-cli auth-config get-contexts
+gocd-cli auth-config rename-context central central-prod
+gocd-cli auth-config delete-context central-prod
 gocd-cli auth-config remove --profile central
 gocd-cli auth-config show --profile central
 `,
@@ -36,6 +60,10 @@ gocd-cli auth-config show --profile central
 	registerAuthConfigCmd.AddCommand(getAuthStoreCommand())
 	registerAuthConfigCmd.AddCommand(getAuthShowCommand())
 	registerAuthConfigCmd.AddCommand(getAuthEraseCommand())
+	registerAuthConfigCmd.AddCommand(getAuthUseContextCommand())
+	registerAuthConfigCmd.AddCommand(getAuthGetContextsCommand())
+	registerAuthConfigCmd.AddCommand(getAuthRenameContextCommand())
+	registerAuthConfigCmd.AddCommand(getAuthDeleteContextCommand())
 
 	for _, command := range registerAuthConfigCmd.Commands() {
 		command.SilenceUsage = true
@@ -46,57 +74,66 @@ gocd-cli auth-config show --profile central
 
 func getAuthStoreCommand() *cobra.Command {
 	authStoreCmd := &cobra.Command{
-		Use:     "store",
-		Short:   "Command to cache the GoCD authorization configuration to be used by the cli",
+		Use:   "store",
+		Short: "Command to cache the GoCD authorization configuration to be used by the cli",
+		Long: `Command to cache the GoCD authorization configuration to be used by the cli.
+When --encrypt is set, the password and token are sealed with a key derived from the passphrase,
+which is read from the environment variable GOCD_CLI_PASSPHRASE or prompted for when not set`,
+		Example: `gocd-cli auth-config store --server-url http://localhost:8153/go --username user --password password --profile central
+GOCD_CLI_PASSPHRASE=passphrase gocd-cli auth-config store --server-url http://localhost:8153/go --auth-token token --encrypt`,
 		Args:    cobra.NoArgs,
-		PreRunE: setCLIClient,
+		PreRunE: setCLIClientWithoutCache,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			cliLogger.Debug("saving authorisation config to cache, so that it can be reused next")
-			home, err := os.UserHomeDir()
-			if err != nil {
-				cliLogger.Errorf("fetching user's home directory errored with '%v'", err)
 
+			contextsFile, contextsCfg, err := loadContexts()
+			if err != nil {
 				return err
 			}
 
-			authConfigDir := filepath.Join(home, goCdCacheDirName)
-			const filePermission = 0o755
-			if err = os.Mkdir(authConfigDir, filePermission); os.IsNotExist(err) {
-				cliLogger.Errorf("creating directory '%s' errored with '%v'", authConfigDir, err)
+			profile := contextsCfg.Current(cliCfg.Profile)
 
-				return err
+			credential := contexts.Credential{
+				Name:             profile,
+				UserName:         cliCfg.Auth.UserName,
+				Password:         cliCfg.Auth.Password,
+				BearerToken:      cliCfg.Auth.BearerToken,
+				NoAuth:           cliCfg.Auth.NoAuth,
+				CredentialHelper: cliCfg.CredentialHelper,
 			}
 
-			authFile := filepath.Join(authConfigDir, setConfigWithProfile())
-			authConfigFile, err := os.Create(authFile)
-			if err != nil {
-				cliLogger.Errorf("creating authfile '%s' errored with '%v'", authFile, err)
+			if encryptAuthConfig {
+				cliLogger.Debugf("--encrypt is opted, secrets of context '%s' would be sealed", profile)
 
-				return err
-			}
+				passphrase, err := getPassphrase(profile)
+				if err != nil {
+					return err
+				}
 
-			cliLogger.Infof("authorisation config would be saved under %s", authConfigFile.Name())
+				if err = credential.Seal(passphrase); err != nil {
+					return err
+				}
+			}
 
-			cfgYAML, err := yaml.Marshal(cliCfg)
-			if err != nil {
-				cliLogger.Errorf("serializing auth config data to yaml errored with '%s'", err)
+			contextsCfg.Set(profile, cliCfg.getContextServer(), credential)
 
-				return err
-			}
+			cliLogger.Infof("authorisation config would be saved as context '%s' under %s", profile, contextsFile)
 
-			//nolint:mirror
-			if _, err = authConfigFile.WriteString(string(cfgYAML)); err != nil {
-				cliLogger.Errorf("writing auth config data to file '%s' errored with '%s'", authConfigFile.Name(), err)
+			if err = contextsCfg.Save(contextsFile); err != nil {
+				cliLogger.Errorf("writing auth config data to file '%s' errored with '%s'", contextsFile, err)
 
 				return err
 			}
 
-			cliLogger.Infof("authorisation config was successfully saved under %s", authConfigFile.Name())
+			cliLogger.Infof("authorisation config was successfully saved as context '%s' under %s", profile, contextsFile)
 
 			return nil
 		},
 	}
 
+	authStoreCmd.PersistentFlags().BoolVarP(&encryptAuthConfig, "encrypt", "", false,
+		"enable this to seal the password and token with a passphrase before caching them")
+
 	return authStoreCmd
 }
 
@@ -105,18 +142,31 @@ func getAuthEraseCommand() *cobra.Command {
 		Use:     "remove",
 		Short:   "Command to remove the cached GoCD authorization configuration that is used by the cli.",
 		Args:    cobra.NoArgs,
-		PreRunE: setCLIClient,
+		PreRunE: setCLIClientWithoutCache,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			home, err := os.UserHomeDir()
+			contextsFile, contextsCfg, err := loadContexts()
 			if err != nil {
-				cliLogger.Errorf("fetching user's home directory errored with '%v'", err)
-
 				return err
 			}
 
-			authConfigFile := filepath.Join(home, goCdCacheDirName, setConfigWithProfile())
+			profile := contextsCfg.Current(cliCfg.Profile)
+
+			cliLogger.Infof("authorisation config of context '%s' saved in '%s' would be cleaned", profile, contextsFile)
+
+			if err = contextsCfg.Delete(profile); err == nil {
+				if err = contextsCfg.Save(contextsFile); err != nil {
+					cliLogger.Errorf("cleaning authorisation config saved in '%s' errored with '%v'", contextsFile, err)
 
-			cliLogger.Infof("authorisation config saved in '%s' would be cleaned", authConfigFile)
+					return err
+				}
+
+				cliLogger.Infof("authorisation config of context '%s' saved in '%s' was cleaned successfully", profile, contextsFile)
+			}
+
+			authConfigFile := filepath.Join(filepath.Dir(contextsFile), fmt.Sprintf(goCdAuthConfigFileName, profile))
+			if _, err = os.Stat(authConfigFile); os.IsNotExist(err) {
+				return nil
+			}
 
 			if err = os.RemoveAll(authConfigFile); err != nil {
 				cliLogger.Errorf("cleaning authorisation config saved in '%s' errored with '%v'", authConfigFile, err)
@@ -135,30 +185,58 @@ func getAuthEraseCommand() *cobra.Command {
 
 func getAuthShowCommand() *cobra.Command {
 	authEraseCmd := &cobra.Command{
-		Use:     "show",
-		Short:   "Command to show the cached GoCD authorization configuration that is used by the cli.",
+		Use:   "show",
+		Short: "Command to show the cached GoCD authorization configuration that is used by the cli.",
+		Example: `gocd-cli auth-config show --profile central
+gocd-cli auth-config show --profile central --reveal`,
 		Args:    cobra.NoArgs,
-		PreRunE: setCLIClient,
+		PreRunE: setCLIClientWithoutCache,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			home, err := os.UserHomeDir()
+			contextsFile, contextsCfg, err := loadContexts()
 			if err != nil {
-				cliLogger.Errorf("fetching user's home directory errored with '%v'", err)
-
 				return err
 			}
 
-			authConfigFile := filepath.Join(home, goCdCacheDirName, setConfigWithProfile())
+			profile := contextsCfg.Current(cliCfg.Profile)
 
-			cliLogger.Infof("authorisation config saved in '%s' would be fetched", authConfigFile)
+			cliLogger.Infof("authorisation config of context '%s' saved in '%s' would be fetched", profile, contextsFile)
 
-			if _, err = os.Stat(authConfigFile); os.IsNotExist(err) {
-				return &errors.CLIError{Message: fmt.Sprintf("no auth config for profile '%s' found", cliCfg.Profile)}
+			server, credential, err := contextsCfg.Resolve(profile)
+			if err != nil {
+				legacyCfg, err := readLegacyConfig(filepath.Dir(contextsFile), profile)
+				if err != nil {
+					return err
+				}
+
+				if legacyCfg == nil {
+					return &errors.CLIError{Message: fmt.Sprintf("no auth config for profile '%s' found", profile)}
+				}
+
+				server = legacyCfg.getContextServer()
+				server.Name = profile
+				credential = contexts.Credential{
+					Name:             profile,
+					UserName:         legacyCfg.Auth.UserName,
+					Password:         legacyCfg.Auth.Password,
+					BearerToken:      legacyCfg.Auth.BearerToken,
+					NoAuth:           legacyCfg.Auth.NoAuth,
+					CredentialHelper: legacyCfg.CredentialHelper,
+				}
 			}
 
-			authConfigData, err := os.ReadFile(authConfigFile)
-			if err != nil {
-				cliLogger.Errorf("reading authorisation config file '%s' errored with '%v'", authConfigFile, err)
+			if !revealAuthConfig {
+				credential = credential.Masked()
+			} else if err = openCredential(&credential); err != nil {
+				return err
+			}
 
+			authConfigData, err := yaml.Marshal(authContext{
+				Name:       profile,
+				Current:    contextsCfg.CurrentContext == profile,
+				Server:     server,
+				Credential: credential,
+			})
+			if err != nil {
 				return err
 			}
 
@@ -166,28 +244,330 @@ func getAuthShowCommand() *cobra.Command {
 		},
 	}
 
+	authEraseCmd.PersistentFlags().BoolVarP(&revealAuthConfig, "reveal", "", false,
+		"enable this to show the secrets of the cached authorization configuration, which are masked otherwise")
+
 	return authEraseCmd
 }
 
-func checkForConfig() (bool, string, error) {
+func getAuthUseContextCommand() *cobra.Command {
+	authUseContextCmd := &cobra.Command{
+		Use:               "use-context",
+		Short:             "Command to set the current-context, that would be used by the cli when --profile is not set",
+		Example:           `gocd-cli auth-config use-context central`,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeContextNames,
+		PreRunE:           setCLIClientWithoutCache,
+		RunE: func(_ *cobra.Command, args []string) error {
+			contextsFile, contextsCfg, err := loadContexts()
+			if err != nil {
+				return err
+			}
+
+			if err = contextsCfg.Use(args[0]); err != nil {
+				return err
+			}
+
+			if err = contextsCfg.Save(contextsFile); err != nil {
+				return err
+			}
+
+			return cliRenderer.Render(fmt.Sprintf("switched to context '%s'", args[0]))
+		},
+	}
+
+	return authUseContextCmd
+}
+
+func getAuthGetContextsCommand() *cobra.Command {
+	authGetContextsCmd := &cobra.Command{
+		Use:     "get-contexts",
+		Short:   "Command to list all the contexts cached by the cli",
+		Example: `gocd-cli auth-config get-contexts -o table`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCLIClientWithoutCache,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			_, contextsCfg, err := loadContexts()
+			if err != nil {
+				return err
+			}
+
+			authContexts := make([]authContext, 0)
+
+			for _, name := range contextsCfg.Names() {
+				server, credential, err := contextsCfg.Resolve(name)
+				if err != nil {
+					return err
+				}
+
+				authContexts = append(authContexts, authContext{
+					Name:       name,
+					Current:    contextsCfg.CurrentContext == name,
+					Server:     server,
+					Credential: contexts.Credential{Name: credential.Name, UserName: credential.UserName, NoAuth: credential.NoAuth},
+				})
+			}
+
+			if cliRenderer.Table {
+				cliCfg.TableData = append(cliCfg.TableData, []string{"Current", "Name", "Server", "Credential"})
+				for _, authCtx := range authContexts {
+					current := ""
+					if authCtx.Current {
+						current = "*"
+					}
+
+					cliCfg.TableData = append(cliCfg.TableData, []string{current, authCtx.Name, authCtx.Server.URL, authCtx.Credential.Name})
+				}
+
+				return cliRenderer.Render(cliCfg.TableData)
+			}
+
+			return cliRenderer.Render(authContexts)
+		},
+	}
+
+	return authGetContextsCmd
+}
+
+func getAuthRenameContextCommand() *cobra.Command {
+	authRenameContextCmd := &cobra.Command{
+		Use:               "rename-context",
+		Short:             "Command to rename a context cached by the cli",
+		Example:           `gocd-cli auth-config rename-context central central-prod`,
+		Args:              cobra.RangeArgs(2, 2), //nolint:mnd
+		ValidArgsFunction: completeContextNames,
+		PreRunE:           setCLIClientWithoutCache,
+		RunE: func(_ *cobra.Command, args []string) error {
+			contextsFile, contextsCfg, err := loadContexts()
+			if err != nil {
+				return err
+			}
+
+			if err = contextsCfg.Rename(args[0], args[1]); err != nil {
+				return err
+			}
+
+			if err = contextsCfg.Save(contextsFile); err != nil {
+				return err
+			}
+
+			return cliRenderer.Render(fmt.Sprintf("context '%s' renamed to '%s'", args[0], args[1]))
+		},
+	}
+
+	return authRenameContextCmd
+}
+
+func getAuthDeleteContextCommand() *cobra.Command {
+	authDeleteContextCmd := &cobra.Command{
+		Use:   "delete-context",
+		Short: "Command to delete a context cached by the cli",
+		Example: `gocd-cli auth-config delete-context central
+gocd-cli auth-config delete-context central -y`,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeContextNames,
+		PreRunE:           setCLIClientWithoutCache,
+		RunE: func(_ *cobra.Command, args []string) error {
+			contextName := args[0]
+
+			contextsFile, contextsCfg, err := loadContexts()
+			if err != nil {
+				return err
+			}
+
+			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete context '%s' [y/n]", contextName)
+
+			if err = cliCfg.confirm(); err != nil {
+				return err
+			}
+
+			if err = contextsCfg.Delete(contextName); err != nil {
+				return err
+			}
+
+			if err = contextsCfg.Save(contextsFile); err != nil {
+				return err
+			}
+
+			return cliRenderer.Render(fmt.Sprintf("context '%s' deleted successfully", contextName))
+		},
+	}
+
+	return authDeleteContextCmd
+}
+
+func (cfg *Config) getContextServer() contexts.Server {
+	return contexts.Server{
+		URL:                cfg.URL,
+		CaPath:             cfg.CaPath,
+		ClientCertPath:     cfg.ClientCertPath,
+		ClientKeyPath:      cfg.ClientKeyPath,
+		InsecureSkipVerify: cfg.InsecureSkip,
+		ProxyURL:           cfg.ProxyURL,
+		NoProxy:            cfg.NoProxy,
+	}
+}
+
+func (cfg *Config) setContextServer(server contexts.Server) {
+	cfg.URL = server.URL
+	cfg.CaPath = server.CaPath
+	cfg.ClientCertPath = server.ClientCertPath
+	cfg.ClientKeyPath = server.ClientKeyPath
+	cfg.InsecureSkip = server.InsecureSkipVerify
+	cfg.ProxyURL = server.ProxyURL
+	cfg.NoProxy = server.NoProxy
+}
+
+// setCLIClientWithoutCache sets the cli client by skipping the cached authorization configuration,
+// as auth-config commands operate on the cache itself and the configurations passed to them should not be overridden by it.
+// Credential helper is skipped for the same reason, so that the credentials fetched by it do not end up in the cache.
+func setCLIClientWithoutCache(cmd *cobra.Command, args []string) error {
+	cliCfg.skipCacheConfig = true
+	cliCfg.skipCredHelper = true
+
+	return setCLIClient(cmd, args)
+}
+
+// checkForConfig looks up the cached authorization configuration of the selected context.
+// Contexts file is looked up first, followed by the legacy per-profile file $HOME/.gocd/auth_config.<profile>.yaml.
+func checkForConfig() (*Config, error) {
 	cliLogger.Debug("searching for authorisation configuration in cache")
 
+	if _, err := os.UserHomeDir(); err != nil {
+		cliLogger.Debugf("cached authorisation configuration would not be used, as fetching user's home directory errored with '%v'", err)
+
+		return nil, nil //nolint:nilnil
+	}
+
+	contextsFile, contextsCfg, err := loadContexts()
+	if err != nil {
+		return nil, err
+	}
+
+	profile := contextsCfg.Current(cliCfg.Profile)
+
+	if server, credential, err := contextsCfg.Resolve(profile); err == nil {
+		cliLogger.Debugf("found context '%s' in %s", profile, contextsFile)
+
+		if err = openCredential(&credential); err != nil {
+			return nil, err
+		}
+
+		cachedCfg := &Config{
+			Auth: gocd.Auth{
+				UserName:    credential.UserName,
+				Password:    credential.Password,
+				BearerToken: credential.BearerToken,
+				NoAuth:      credential.NoAuth,
+			},
+			CredentialHelper: credential.CredentialHelper,
+		}
+		cachedCfg.setContextServer(server)
+
+		return cachedCfg, nil
+	}
+
+	return readLegacyConfig(filepath.Dir(contextsFile), profile)
+}
+
+// readLegacyConfig reads the authorization configuration cached by older versions under $HOME/.gocd/auth_config.<profile>.yaml.
+func readLegacyConfig(configDir, profile string) (*Config, error) {
+	configPath := filepath.Join(configDir, fmt.Sprintf(goCdAuthConfigFileName, profile))
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		cliLogger.Warnf("no authorisation configuration with profile '%s' found in cache", profile)
+
+		return nil, nil //nolint:nilnil
+	}
+
+	cliLogger.Debugf("found legacy authorization configuration %s, consider running 'auth-config store' to migrate it to contexts", configPath)
+
+	yamlConfig, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedCfg := new(Config)
+	if err = ghodssYAML.Unmarshal(yamlConfig, cachedCfg); err != nil {
+		return nil, err
+	}
+
+	return cachedCfg, nil
+}
+
+// getPassphrase reads the passphrase used for sealing the credentials, from the environment if set, else prompts for it.
+func getPassphrase(profile string) (string, error) {
+	if passphrase, ok := os.LookupEnv(goCdPassphraseEnvVar); ok && len(passphrase) != 0 {
+		cliLogger.Debugf("passphrase is read from the environment variable '%s'", goCdPassphraseEnvVar)
+
+		return passphrase, nil
+	}
+
+	if cliCfg.nonInteractive {
+		return "", &errors.CLIError{Message: fmt.Sprintf("credentials of context '%s' are sealed, set the passphrase via '%s'", profile, goCdPassphraseEnvVar)}
+	}
+
+	cliShellReadConfig.ShellMessage = fmt.Sprintf("enter the passphrase to seal/unseal the credentials of context '%s'", profile)
+
+	passphrase, err := cliShellReadConfig.SecretReader()
+	if err != nil {
+		return "", err
+	}
+
+	if len(passphrase) == 0 {
+		return "", &errors.CLIError{
+			Message: fmt.Sprintf("passphrase is required to seal/unseal the credentials, either set it via '%s' or enter it when prompted", goCdPassphraseEnvVar),
+		}
+	}
+
+	return passphrase, nil
+}
+
+func openCredential(credential *contexts.Credential) error {
+	if !credential.IsSealed() {
+		return nil
+	}
+
+	cliLogger.Debugf("credentials of context '%s' are sealed, hence unsealing them", credential.Name)
+
+	passphrase, err := getPassphrase(credential.Name)
+	if err != nil {
+		return err
+	}
+
+	return credential.Open(passphrase)
+}
+
+func loadContexts() (string, *contexts.Config, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return false, "", err
+		cliLogger.Errorf("fetching user's home directory errored with '%v'", err)
+
+		return "", nil, err
 	}
 
-	configPath := filepath.Join(home, goCdCacheDirName, setConfigWithProfile())
+	contextsFile := filepath.Join(home, goCdCacheDirName, goCdContextsFileName)
 
-	if _, err = os.Stat(configPath); os.IsNotExist(err) {
-		cliLogger.Warnf("no authorisation configuration with profile '%s' found in cache", cliCfg.Profile)
+	contextsCfg, err := contexts.Load(contextsFile)
+	if err != nil {
+		cliLogger.Errorf("reading contexts file '%s' errored with '%v'", contextsFile, err)
 
-		return false, "", nil
+		return "", nil, err
 	}
 
-	return true, configPath, nil
+	return contextsFile, contextsCfg, nil
 }
 
-func setConfigWithProfile() string {
-	return fmt.Sprintf(goCdAuthConfigFileName, cliCfg.Profile)
+// getProfileName returns the name of the context in use, the current context is in use when --profile is not passed.
+func (cfg *Config) getProfileName() string {
+	if len(cfg.Profile) != 0 {
+		return cfg.Profile
+	}
+
+	_, contextsCfg, err := loadContexts()
+	if err != nil {
+		return ""
+	}
+
+	return contextsCfg.Current("")
 }
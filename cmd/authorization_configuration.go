@@ -1,19 +1,14 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 func registerAuthorizationConfigCommand() *cobra.Command {
@@ -45,11 +40,12 @@ GET/CREATE/UPDATE/DELETE cluster profiles present in GoCD`,
 
 func getAuthConfigCommand() *cobra.Command {
 	authConfigGetCmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET a authorization configuration with all specified configurations in GoCD [https://api.gocd.org/current/#get-an-authorization-configuration]",
-		Example: "gocd-cli authorization get ldap -o yaml",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get",
+		Short:             "Command to GET a authorization configuration with all specified configurations in GoCD [https://api.gocd.org/current/#get-an-authorization-configuration]",
+		Example:           "gocd-cli authorization get ldap -o yaml",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceAuthConfig),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetAuthConfig(args[0])
@@ -152,17 +148,8 @@ gocd-cli authorization update --from-file config-repo.yaml --create -o yaml -y`,
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &authConfig); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &authConfig); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &authConfig); err != nil {
+				return err
 			}
 
 			authConfigFetched, err := client.GetAuthConfig(authConfig.ID)
@@ -176,22 +163,19 @@ gocd-cli authorization update --from-file config-repo.yaml --create -o yaml -y`,
 				}
 			}
 
-			if len(authConfig.ETAG) == 0 {
-				authConfig.ETAG = authConfigFetched.ETAG
+			if authConfig, err = rebaseResource(authConfigFetched, authConfig); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "authorization", authConfigFetched.ID)
 
-			existing, err := diffCfg.String(authConfigFetched)
-			if err != nil {
+			if err = cliCfg.CheckDiffAndAllow(authConfigFetched, authConfig, object.String()); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
-				return err
-			}
-
-			response, err := client.UpdateAuthConfig(authConfig)
+			response, err := updateResource(authConfigFetched, authConfig, func() (gocd.CommonConfig, error) {
+				return client.GetAuthConfig(authConfig.ID)
+			}, client.UpdateAuthConfig)
 			if err != nil {
 				return err
 			}
@@ -204,6 +188,8 @@ gocd-cli authorization update --from-file config-repo.yaml --create -o yaml -y`,
 	authConfigUpdateCommand.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a config repo by this name doesn't already exist, run create")
 
+	setUpdateMergeHelp(authConfigUpdateCommand)
+
 	return authConfigUpdateCommand
 }
 
@@ -213,23 +199,15 @@ func getDeleteAuthConfigCommand() *cobra.Command {
 		Short: "Command to DELETE the specified authorization configuration present in GoCD [https://api.gocd.org/current/#delete-an-authorization-configuration]",
 		Example: `gocd-cli authorization delete helm-images
 gocd-cli authorization delete helm-images -y`,
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceAuthConfig),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			AuthConfigName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete authorization-configuration repo '%s' [y/n]", AuthConfigName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteAuthConfig(AuthConfigName); err != nil {
@@ -290,17 +268,8 @@ func createAuthConfig(cmd *cobra.Command, _ []string) error {
 
 	fmt.Println(object.String())
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &authConfig); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &authConfig); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &authConfig); err != nil {
+		return err
 	}
 
 	if _, err = client.CreateAuthConfig(authConfig); err != nil {
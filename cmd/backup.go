@@ -1,16 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
-	"os"
+	"fmt"
 	"strconv"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
 	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -78,17 +75,8 @@ func createOrUpdateBackupConfig() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &backupConfig); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &backupConfig); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &backupConfig); err != nil {
+				return err
 			}
 
 			if err = client.CreateOrUpdateBackupConfig(backupConfig); err != nil {
@@ -113,17 +101,8 @@ func deleteBackupConfig() *cobra.Command {
 		RunE: func(_ *cobra.Command, _ []string) error {
 			cliShellReadConfig.ShellMessage = "do you want to delete GoCD's backup configuration [y/n]"
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteBackupConfig(); err != nil {
@@ -170,6 +149,10 @@ func scheduleBackup() *cobra.Command {
 				return err
 			}
 
+			if cliCfg.DryRun {
+				return errDryRunWithheld
+			}
+
 			retryAfter, err := strconv.Atoi(response["RetryAfter"])
 			if err != nil {
 				return err
@@ -184,8 +167,8 @@ func scheduleBackup() *cobra.Command {
 			var latestBackupStatus string
 			for {
 				if currentRetryCount > backupRetry {
-					cliLogger.Fatalf("maximum retry count of '%d' crossed with current count '%d', still backup is not ready yet with status '%s'. Exitting",
-						backupRetry, currentRetryCount, latestBackupStatus)
+					return &errors.ServerUnavailableError{Message: fmt.Sprintf("maximum retry count of '%d' crossed with current count '%d', still backup is not ready yet with status '%s'",
+						backupRetry, currentRetryCount, latestBackupStatus)}
 				}
 
 				response, err := client.GetBackup(backupID)
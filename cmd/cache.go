@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/cache"
+	"github.com/nikhilsbhat/gocd-cli/pkg/credhelper"
+	"github.com/spf13/cobra"
+)
+
+const (
+	goCdCacheSubDirName        = "cache"
+	goCdCompletionCacheDirName = "completions"
+	goCdResponseCacheDirName   = "responses"
+	goCdVersionCacheDirName    = "versions"
+	goCdCredentialCacheDirName = "credentials"
+
+	versionCacheTTL = 30 * 24 * time.Hour
+)
+
+var cacheClearAll bool
+
+func registerCacheCommand() *cobra.Command {
+	registerCacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Command to manage the local cache of the cli",
+		Long: `Command to manage the local cache of the cli, saved under $HOME/.gocd/cache.
+The cache holds the responses of GET calls made to GoCD server when --cache-ttl is set, the resource names fetched for shell completions,
+the versions of the resources fetched when --save-versions is set, that the updates based on them are merged from when they conflict with the changes made since,
+and the credentials fetched by the credential helper till they expire.`,
+		Example: `gocd-cli cache clear
+gocd-cli cache clear --all`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Usage()
+		},
+	}
+
+	registerCacheCmd.SetUsageTemplate(getUsageTemplate())
+
+	registerCacheCmd.AddCommand(getCacheClearCommand())
+
+	for _, command := range registerCacheCmd.Commands() {
+		command.SilenceUsage = true
+	}
+
+	return registerCacheCmd
+}
+
+func getCacheClearCommand() *cobra.Command {
+	cacheClearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Command to clear the cached responses of GoCD server and the names cached for shell completions",
+		Long: `Command to clear the cached responses of GoCD server and the names cached for shell completions.
+The versions saved by --save-versions, that the updates based on them are merged from, and the credentials cached from the credential helper
+are left as they are, unless --all is set.`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCLIClientWithoutCache,
+		Example: `gocd-cli cache clear
+gocd-cli cache clear --all`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cacheDir, err := getCacheDir()
+			if err != nil {
+				return err
+			}
+
+			if cacheClearAll {
+				if err = os.RemoveAll(cacheDir); err != nil {
+					return err
+				}
+
+				// the credentials cached in memory by the shell session are cleared as well.
+				goCdCredentialHelpers = make(map[string]*credhelper.Helper)
+
+				return cliRenderer.Render(fmt.Sprintf("cache under '%s' was cleared", cacheDir))
+			}
+
+			for _, dirName := range []string{goCdResponseCacheDirName, goCdCompletionCacheDirName} {
+				if err = os.RemoveAll(filepath.Join(cacheDir, dirName)); err != nil {
+					return err
+				}
+			}
+
+			return cliRenderer.Render(fmt.Sprintf("cached responses and completions under '%s' were cleared, use --all to clear the versions and the credentials as well", cacheDir))
+		},
+	}
+
+	cacheClearCmd.PersistentFlags().BoolVarP(&cacheClearAll, "all", "", false,
+		"enable this to clear the versions saved by --save-versions and the credentials cached from the credential helper as well")
+
+	return cacheClearCmd
+}
+
+func getCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, goCdCacheDirName, goCdCacheSubDirName), nil
+}
+
+func getResponseCache(ttl time.Duration) (*cache.Cache, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return cache.New(filepath.Join(cacheDir, goCdResponseCacheDirName), ttl), nil
+}
+
+func getVersionCache() (*cache.Cache, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := cache.New(filepath.Join(cacheDir, goCdVersionCacheDirName), versionCacheTTL)
+
+	// the versions hold the resources as they were fetched, the ones past their TTL are removed rather than left on the disk.
+	if err = versions.Prune(); err != nil {
+		cliLogger.Debugf("removing the expired versions under '%s' errored with '%v'", versions.Dir(), err)
+	}
+
+	return versions, nil
+}
+
+func getCompletionCache() *cache.Cache {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return cache.New(filepath.Join(cacheDir, goCdCompletionCacheDirName), completionCacheTTL)
+}
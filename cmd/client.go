@@ -3,13 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/ghodss/yaml"
-	"github.com/nikhilsbhat/common/diff"
 	"github.com/nikhilsbhat/common/renderer"
 	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
 	"github.com/nikhilsbhat/gocd-cli/pkg/utils"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
@@ -18,51 +16,77 @@ import (
 
 var (
 	client                 gocd.GoCd
-	cliRenderer            renderer.Config
+	cliRenderer            outputRenderer
 	cliShellReadConfig     *utils.ReadConfig
-	diffCfg                *diff.Config
 	supportedOutputFormats = []string{"yaml", "y", "json", "j", "csv", "c", "table", "t"}
+	authFlags              = []string{"username", "password", "auth-token", "no-auth", "credential-helper"}
 )
 
-func setCLIClient(_ *cobra.Command, _ []string) error {
-	SetLogger(cliCfg.LogLevel)
-
-	localConfig, localConfigPath, err := checkForConfig()
+func setCLIClient(cmd *cobra.Command, args []string) error {
+	reuseClient, err := setCLIConfig(cmd, args)
 	if err != nil {
 		return err
 	}
 
-	if localConfig && !cliCfg.skipCacheConfig {
-		cliLogger.Debugf("found authorization configuration in cache, loading config from %s", localConfigPath)
-
-		yamlConfig, err := os.ReadFile(localConfigPath)
-		if err != nil {
+	if !reuseClient {
+		if err = setGoCDClient(); err != nil {
 			return err
 		}
+	}
 
-		if err := yaml.Unmarshal(yamlConfig, &cliCfg); err != nil {
-			return err
-		}
+	return setCLIOutput()
+}
+
+// setCLIConfig resolves the configurations of the command from its flags, the environment and the cached profile,
+// it returns true when the client of the shell session could be reused instead of setting a new one.
+func setCLIConfig(cmd *cobra.Command, args []string) (bool, error) {
+	setRunningCommand(cmd, args)
+
+	if goCdShell != nil && goCdShell.canReuseClient(cmd) {
+		SetLogger(cliCfg.LogLevel)
+
+		cliLogger.Debug("reusing the client of the shell session")
 
-		cliLogger.Debug("authorization configuration loaded from cache successfully")
+		return true, nil
 	}
 
-	if len(cliCfg.CaPath) != 0 {
-		cliLogger.Debug("CA based auth is enabled, hence reading CA from the path")
+	configuredFlags, err := setFlagsFromEnv(cmd)
+	if err != nil {
+		return false, err
+	}
+
+	SetLogger(cliCfg.LogLevel)
+
+	inputOptions := []utils.Options{{Name: "yes", Short: "y"}, {Name: "no", Short: "n"}}
+	cliShellReadConfig = utils.NewReadConfig("gocd-cli", "", inputOptions, cliLogger)
 
-		caAbs, err := filepath.Abs(cliCfg.CaPath)
+	if !cliCfg.skipCacheConfig {
+		cachedConfig, err := checkForConfig()
 		if err != nil {
-			return err
+			return false, err
 		}
 
-		caContent, err := os.ReadFile(caAbs)
-		if err != nil {
-			return err
+		if cachedConfig != nil {
+			cliLogger.Debug("found authorization configuration in cache, loading config from it")
+
+			cliCfg.setCachedConfig(cachedConfig, configuredFlags)
+
+			cliLogger.Debug("authorization configuration loaded from cache successfully")
 		}
+	}
+
+	return false, nil
+}
 
-		client = gocd.NewClient(cliCfg.URL, cliCfg.Auth, cliCfg.APILogLevel, caContent)
-	} else {
-		client = gocd.NewClient(cliCfg.URL, cliCfg.Auth, cliCfg.APILogLevel, nil)
+// setGoCDClient sets the client talking to GoCD server with the configurations resolved, fetching the credentials from the credential helper if required.
+func setGoCDClient() error {
+	if err := cliCfg.setAuthFromCredentialHelper(); err != nil {
+		return err
+	}
+
+	var err error
+	if client, err = cliCfg.getGoCDClient(); err != nil {
+		return err
 	}
 
 	client.SetRetryWaitTime(cliCfg.APIRetryInterval)
@@ -71,6 +95,35 @@ func setCLIClient(_ *cobra.Command, _ []string) error {
 		client.SetRetryCount(cliCfg.APIRetryCount)
 	}
 
+	return nil
+}
+
+// getProfileClient returns the client of the context passed, along with the loopback gateway that it is routed through if any.
+// The configurations and the client set up for the command are left untouched, the output should be set up again by the caller.
+func getProfileClient(cmd *cobra.Command, args []string, profile string) (gocd.GoCd, *transport.Gateway, error) {
+	baseCfg, baseClient, baseGateway, baseDryRun := cliCfg, client, goCdGateway, goCdDryRun
+
+	defer func() {
+		cliCfg, client, goCdGateway, goCdDryRun = baseCfg, baseClient, baseGateway, baseDryRun
+	}()
+
+	if err := cmd.Root().PersistentFlags().Set("profile", profile); err != nil {
+		return nil, nil, err
+	}
+
+	if err := setCLIClient(cmd, args); err != nil {
+		return nil, nil, err
+	}
+
+	if goCdGateway == baseGateway {
+		return client, nil, nil
+	}
+
+	return client, goCdGateway, nil
+}
+
+// setCLIOutput sets the writer and renderer configurations as per the output flags.
+func setCLIOutput() error {
 	writer := os.Stdout
 
 	if len(cliCfg.ToFile) != 0 {
@@ -92,17 +145,82 @@ func setCLIClient(_ *cobra.Command, _ []string) error {
 		return &errors.CLIError{Message: errMsg}
 	}
 
-	diffCfg = diff.NewDiff(cliCfg.OutputFormat, cliCfg.NoColor, cliLogger)
-
 	cliCfg.setOutputFormats()
-	cliRenderer = renderer.GetRenderer(writer, cliLogger, cliCfg.NoColor, cliCfg.yaml, cliCfg.json, cliCfg.csv, cliCfg.table)
-
-	inputOptions := []utils.Options{{Name: "yes", Short: "y"}, {Name: "no", Short: "n"}}
-	cliShellReadConfig = utils.NewReadConfig("gocd-cli", "", inputOptions, cliLogger)
+	cliRenderer = outputRenderer{Config: renderer.GetRenderer(writer, cliLogger, cliCfg.NoColor, cliCfg.yaml, cliCfg.json, cliCfg.csv, cliCfg.table)}
 
 	return nil
 }
 
+// outputRenderer renders the output of the commands, the messages of the calls withheld by --dry-run are rendered as withheld rather than as succeeded.
+type outputRenderer struct {
+	renderer.Config
+}
+
+// Render renders the value as per the output format set, with --dry-run the messages like 'pipeline sample updated successfully'
+// are rendered as 'pipeline sample would have been updated, the call was withheld by --dry-run'.
+func (output *outputRenderer) Render(value interface{}) error {
+	if message, ok := value.(string); ok && goCdDryRun != nil {
+		value = getDryRunMessage(message)
+	}
+
+	return output.Config.Render(value)
+}
+
+func getDryRunMessage(message string) string {
+	action, found := strings.CutSuffix(message, " successfully")
+	if !found {
+		return message
+	}
+
+	index := strings.LastIndex(action, " ")
+	if index == -1 {
+		return message
+	}
+
+	return fmt.Sprintf("%s would have been %s, the call was withheld by --dry-run", action[:index], action[index+1:])
+}
+
+// setCachedConfig sets the configurations from the cache, only for the ones that are not configured via flags or environment variables.
+// Credentials are considered as a whole, if any of them are configured the cached credentials are not used at all.
+func (cfg *Config) setCachedConfig(cachedConfig *Config, configuredFlags map[string]bool) {
+	if len(cachedConfig.URL) != 0 && !configuredFlags["server-url"] {
+		cfg.URL = cachedConfig.URL
+	}
+
+	if len(cachedConfig.CaPath) != 0 && !configuredFlags["ca-file-path"] {
+		cfg.CaPath = cachedConfig.CaPath
+	}
+
+	if len(cachedConfig.ClientCertPath) != 0 && !configuredFlags["client-cert-path"] {
+		cfg.ClientCertPath = cachedConfig.ClientCertPath
+	}
+
+	if len(cachedConfig.ClientKeyPath) != 0 && !configuredFlags["client-key-path"] {
+		cfg.ClientKeyPath = cachedConfig.ClientKeyPath
+	}
+
+	if cachedConfig.InsecureSkip && !configuredFlags["insecure-skip-verify"] {
+		cfg.InsecureSkip = cachedConfig.InsecureSkip
+	}
+
+	if len(cachedConfig.ProxyURL) != 0 && !configuredFlags["proxy-url"] {
+		cfg.ProxyURL = cachedConfig.ProxyURL
+	}
+
+	if len(cachedConfig.NoProxy) != 0 && !configuredFlags["no-proxy"] {
+		cfg.NoProxy = cachedConfig.NoProxy
+	}
+
+	if funk.Contains(authFlags, func(flag string) bool { return configuredFlags[flag] }) {
+		cliLogger.Debug("credentials are configured via flags or environment variables, hence cached credentials would not be used")
+
+		return
+	}
+
+	cfg.Auth = cachedConfig.Auth
+	cfg.CredentialHelper = cachedConfig.CredentialHelper
+}
+
 func (cfg *Config) validateOutputFormats() bool {
 	if len(cfg.OutputFormat) == 0 {
 		return true
@@ -112,6 +230,8 @@ func (cfg *Config) validateOutputFormats() bool {
 }
 
 func (cfg *Config) setOutputFormats() {
+	cfg.yaml, cfg.json, cfg.csv, cfg.table = false, false, false, false
+
 	switch strings.ToLower(cfg.OutputFormat) {
 	case "yaml", "y":
 		cfg.yaml = true
@@ -139,3 +259,8 @@ func (cfg *Config) GetOutputFormat() string {
 		return ""
 	}
 }
+
+// isSameServer returns true when the URLs point to the same GoCD server, the trailing slashes are not considered.
+func isSameServer(left, right string) bool {
+	return strings.TrimSuffix(left, "/") == strings.TrimSuffix(right, "/")
+}
@@ -1,19 +1,14 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 func registerClusterProfilesCommand() *cobra.Command {
@@ -89,10 +84,11 @@ func getClusterProfilesCommand() *cobra.Command {
 
 func getClusterProfileCommand() *cobra.Command {
 	getClusterProfileCmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET a specific cluster profile present in GoCD [https://api.gocd.org/current/#get-a-cluster-profile]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get",
+		Short:             "Command to GET a specific cluster profile present in GoCD [https://api.gocd.org/current/#get-a-cluster-profile]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceClusterProfile),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetClusterProfile(args[0])
@@ -156,17 +152,8 @@ func updateClusterProfileCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &commonCfg); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &commonCfg); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &commonCfg); err != nil {
+				return err
 			}
 
 			clusterProfileFetched, err := client.GetClusterProfile(commonCfg.ID)
@@ -180,22 +167,19 @@ func updateClusterProfileCommand() *cobra.Command {
 				}
 			}
 
-			if len(commonCfg.ETAG) == 0 {
-				commonCfg.ETAG = clusterProfileFetched.ETAG
+			if commonCfg, err = rebaseResource(clusterProfileFetched, commonCfg); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "cluster-profile", clusterProfileFetched.ID)
 
-			existing, err := diffCfg.String(clusterProfileFetched)
-			if err != nil {
+			if err = cliCfg.CheckDiffAndAllow(clusterProfileFetched, commonCfg, object.String()); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
-				return err
-			}
-
-			response, err := client.UpdateClusterProfile(commonCfg)
+			response, err := updateResource(clusterProfileFetched, commonCfg, func() (gocd.CommonConfig, error) {
+				return client.GetClusterProfile(commonCfg.ID)
+			}, client.UpdateClusterProfile)
 			if err != nil {
 				return err
 			}
@@ -211,30 +195,24 @@ func updateClusterProfileCommand() *cobra.Command {
 	updateClusterProfileCmd.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a cluster profile by this name doesn't already exist, run create")
 
+	setUpdateMergeHelp(updateClusterProfileCmd)
+
 	return updateClusterProfileCmd
 }
 
 func deleteClusterProfileCommand() *cobra.Command {
 	deleteClusterProfileCmd := &cobra.Command{
-		Use:     "delete",
-		Short:   "Command to DELETE a specific cluster profile present in GoCD [https://api.gocd.org/current/#delete-a-cluster-profile]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "delete",
+		Short:             "Command to DELETE a specific cluster profile present in GoCD [https://api.gocd.org/current/#delete-a-cluster-profile]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceClusterProfile),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			clusterProfile := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete cluster profile '%s' [y/n]", clusterProfile)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteClusterProfile(clusterProfile); err != nil {
@@ -293,17 +271,8 @@ func createClusterProfile(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &commonCfg); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &commonCfg); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &commonCfg); err != nil {
+		return err
 	}
 
 	response, err := client.CreateClusterProfile(commonCfg)
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	gocderrors "github.com/nikhilsbhat/gocd-sdk-go/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -17,19 +23,81 @@ func init() {
 }
 
 // Main will take the workload of executing/starting the cli, when the command is passed to it.
+// The process exits with the exit code identified by errors.ExitCode, when the command fails.
 func Main() {
-	if err := execute(os.Args[1:]); err != nil {
-		log.Fatal(err)
+	err := execute(os.Args[1:])
+	if err == nil {
+		return
 	}
+
+	os.Exit(reportError(err))
 }
 
 // execute will actually execute the cli by taking the arguments passed to cli.
 func execute(args []string) error {
+	registerExtensionCommands(goCDCommand, args)
 	goCDCommand.SetArgs(args)
 
-	if _, err := goCDCommand.ExecuteC(); err != nil {
+	_, err := goCDCommand.ExecuteC()
+
+	return commandResult(err)
+}
+
+// commandResult returns the error the command should fail with. The commands stopped by --plan-out once the plan is saved have not failed,
+// neither have the ones stopped by errDryRunWithheld, the calls withheld by --dry-run are reported instead.
+func commandResult(err error) error {
+	eraseCredentialHelperCache(err)
+
+	if goerrors.Is(err, errPlanSaved) {
+		return nil
+	}
+
+	if goCdDryRun == nil {
 		return err
 	}
 
-	return nil
+	if withheld := goCdDryRun.Withheld(); withheld != 0 {
+		cliLogger.Infof("dry run, %d call(s) that would modify GoCD server were not sent", withheld)
+	}
+
+	if goerrors.Is(err, errDryRunWithheld) {
+		return nil
+	}
+
+	return err
+}
+
+// reportError prints the error to stderr and returns the exit code for it.
+// With '-o json' the error is printed as errors.Envelope, so that the scripts wrapping the cli could parse it.
+func reportError(err error) int {
+	var notFoundError *gocderrors.NonFoundError
+	if goerrors.As(err, &notFoundError) {
+		err = &errors.NotFoundError{Message: err.Error()}
+	}
+
+	exitCode := errors.ExitCode(err)
+
+	if outputFormat := strings.ToLower(cliCfg.OutputFormat); outputFormat == "json" || outputFormat == "j" {
+		envelope, marshalErr := json.Marshal(errors.NewEnvelope(err))
+		if marshalErr == nil {
+			fmt.Fprintln(os.Stderr, string(envelope))
+
+			return exitCode
+		}
+	}
+
+	var externalCommandError *errors.ExternalCommandError
+
+	switch {
+	case goerrors.As(err, &externalCommandError):
+		// external commands report their own errors, only the exit code is passed through.
+	case exitCode == errors.ExitCodeNoOp && cliLogger != nil:
+		cliLogger.Info(err)
+	case exitCode == errors.ExitCodeUserDeclined && cliLogger != nil:
+		cliLogger.Warn(err)
+	default:
+		log.Println(err)
+	}
+
+	return exitCode
 }
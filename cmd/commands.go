@@ -15,7 +15,13 @@ type cliCommands struct {
 type Config struct {
 	URL              string        `yaml:"url,omitempty"`
 	CaPath           string        `yaml:"ca_path,omitempty"`
+	ClientCertPath   string        `yaml:"client_cert_path,omitempty"`
+	ClientKeyPath    string        `yaml:"client_key_path,omitempty"`
+	InsecureSkip     bool          `yaml:"insecure_skip_verify,omitempty"`
+	ProxyURL         string        `yaml:"proxy_url,omitempty"`
+	NoProxy          string        `yaml:"no_proxy,omitempty"`
 	Auth             gocd.Auth     `yaml:"auth,omitempty"`
+	CredentialHelper string        `yaml:"credential_helper,omitempty"`
 	Yes              bool          `yaml:"-"`
 	NoColor          bool          `yaml:"-"`
 	Watch            bool          `yaml:"-"`
@@ -25,6 +31,15 @@ type Config struct {
 	APILogLevel      string        `yaml:"-"`
 	FromFile         string        `yaml:"-"`
 	ToFile           string        `yaml:"-"`
+	Record           string        `yaml:"-"`
+	Replay           string        `yaml:"-"`
+	NoCache          bool          `yaml:"-"`
+	NoHistory        bool          `yaml:"-"`
+	SaveVersions     bool          `yaml:"-"`
+	CacheCredentials bool          `yaml:"-"`
+	DryRun           bool          `yaml:"-"`
+	PlanOut          string        `yaml:"-"`
+	CacheTTL         time.Duration `yaml:"-"`
 	TableData        [][]string    `yaml:"-"`
 	APIRetryCount    int           `yaml:"-"`
 	APIRetryInterval int           `yaml:"-"`
@@ -34,6 +49,8 @@ type Config struct {
 	csv              bool          `yaml:"-"`
 	table            bool          `yaml:"-"`
 	skipCacheConfig  bool
+	skipCredHelper   bool
+	nonInteractive   bool
 }
 
 func SetGoCDCliCommands() *cobra.Command {
@@ -67,6 +84,20 @@ func getGoCDCliCommands() *cobra.Command {
 	command.commands = append(command.commands, registerServerConfigCommand())
 	command.commands = append(command.commands, registerIHaveCommand())
 	command.commands = append(command.commands, registerRolesCommand())
+	command.commands = append(command.commands, registerCacheCommand())
+	command.commands = append(command.commands, registerShellCommand())
+	command.commands = append(command.commands, registerApplyPlanCommand())
+	command.commands = append(command.commands, registerApplyCommand())
+	command.commands = append(command.commands, registerExportCommand())
+	command.commands = append(command.commands, registerDriftCommand())
+	command.commands = append(command.commands, registerMigrateCommand())
+	command.commands = append(command.commands, registerCompareCommand())
+	command.commands = append(command.commands, registerEditCommand())
+	command.commands = append(command.commands, registerHistoryCommand())
+	command.commands = append(command.commands, registerRollbackCommand())
+	command.commands = append(command.commands, registerTrackCommand())
+	command.commands = append(command.commands, registerSchemaCommand())
+	command.commands = append(command.commands, registerSkeletonCommand())
 
 	return command.prepareCommands()
 }
@@ -85,9 +116,12 @@ func (c *cliCommands) prepareCommands() *cobra.Command {
 
 func getRootCommand() *cobra.Command {
 	rootCommand := &cobra.Command{
-		Use:     "gocd-cli",
-		Short:   "Command line interface for GoCD",
-		Long:    `Command line interface for GoCD that helps in interacting with GoCD CI/CD server`,
+		Use:   "gocd-cli",
+		Short: "Command line interface for GoCD",
+		Long: `Command line interface for GoCD that helps in interacting with GoCD CI/CD server.
+
+Every global flag can also be set using an environment variable prefixed with GOCD_, ex: GOCD_SERVER_URL for --server-url
+and GOCD_AUTH_TOKEN for --auth-token. The precedence is flag > environment variable > cached profile > default.`,
 		PreRunE: setCLIClient,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return cmd.Usage()
@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-cli/pkg/structdiff"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+// kindPlugin is the kind of the plugins installed on GoCD server, they could only be compared and not applied.
+const kindPlugin = "Plugin"
+
+// States of a resource on a GoCD server, when compared across the profiles.
+const (
+	compareStatePresent = "present"
+	compareStateAbsent  = "absent"
+	compareStateDiffers = "differs"
+)
+
+// compareMaskedSecret replaces the encrypted values before comparing the resources, since the servers encrypt them with their own cipher keys.
+// The secure values are reported only when present on one of the servers, and never with their ciphertext.
+const compareMaskedSecret = "(secure value)"
+
+var (
+	compareProfiles []string
+	compareKinds    []string
+	compareNames    []string
+	compareDiff     bool
+	compareClients  []gocd.GoCd
+	compareGateways []*transport.Gateway
+)
+
+// compareOmittedFields are the fields of the resources that are expected to differ between the servers, they are not compared.
+var compareOmittedFields = map[string][]string{
+	kindPlugin: {"plugin_file_location"},
+}
+
+// comparedResource is a resource compared across the profiles, the diffs are against the first profile that has the resource.
+type comparedResource struct {
+	Kind   string            `json:"kind" yaml:"kind"`
+	Name   string            `json:"name" yaml:"name"`
+	States map[string]string `json:"states" yaml:"states"`
+	Diffs  map[string]string `json:"diffs,omitempty" yaml:"diffs,omitempty"`
+}
+
+func registerCompareCommand() *cobra.Command {
+	compareCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Command to COMPARE the resources across the GoCD servers of two or more profiles",
+		Long: fmt.Sprintf(`Command to COMPARE the resources across the GoCD servers of two or more profiles.
+The resources of the kinds selected are fetched from every server and rendered as a matrix, with the state of every resource on each server:
+  present  - the resource exists on the server, and is the same as on the first profile that has it
+  absent   - the resource does not exist on the server
+  differs  - the resource exists on the server, but differs from the one on the first profile that has it
+
+The resources are compared structurally, the order of the lists that are sets is not considered. The encrypted values are compared
+only for their presence, since every server encrypts them with its own cipher key.
+The diffs of the resources that differ are shown with --diff. The command exits with code 10 when the servers are not identical.
+The resources defined in config repos are not compared. Kinds supported are: %s.`, strings.Join(getCompareKinds(), ", ")),
+		Example: `gocd-cli compare --profile staging --profile production --kind environment,role,plugin
+gocd-cli compare --profile staging --profile production --kind ElasticAgentProfile --name 'k8s-*' --diff`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCompareClients,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			defer closeCompareGateways()
+
+			kinds, err := selectKinds(getCompareKinds(), compareKinds)
+			if err != nil {
+				return err
+			}
+
+			resources, err := compareResources(kinds)
+			if err != nil {
+				return err
+			}
+
+			if err = renderComparedResources(resources); err != nil {
+				return err
+			}
+
+			var differing int
+
+			for _, resource := range resources {
+				if !isIdentical(resource) {
+					differing++
+				}
+			}
+
+			if differing != 0 {
+				return &errors.DriftError{Message: fmt.Sprintf("%d resource(s) are not identical across profiles %s", differing, strings.Join(compareProfiles, ", "))}
+			}
+
+			return nil
+		},
+	}
+
+	registerKindFlag(compareCmd, &compareKinds, getCompareKinds(), "kinds of the resources to be compared, defaults to all the kinds supported")
+
+	compareCmd.PersistentFlags().StringSliceVarP(&compareProfiles, "profile", "", nil,
+		"names of the contexts of the servers to be compared, should be passed at least twice")
+	compareCmd.PersistentFlags().StringSliceVarP(&compareNames, "name", "", nil,
+		"names of the resources to be compared, supports glob patterns (ex: 'k8s-*'), defaults to all the resources")
+	compareCmd.PersistentFlags().BoolVarP(&compareDiff, "diff", "", false,
+		"set this to show the diffs of the resources that differ across the servers")
+
+	if err := compareCmd.MarkPersistentFlagRequired("profile"); err != nil {
+		cliLogger.Fatalf("%v", err)
+	}
+
+	registerFlagCompletion(compareCmd, "profile", completeContextNames)
+
+	compareCmd.SetUsageTemplate(getUsageTemplate())
+	compareCmd.SilenceUsage = true
+
+	return compareCmd
+}
+
+// setCompareClients sets up the clients of all the profiles to be compared.
+func setCompareClients(cmd *cobra.Command, args []string) error {
+	const minProfiles = 2
+
+	if len(compareProfiles) < minProfiles {
+		return &errors.ValidationError{Message: "--profile should be passed at least twice, to compare the servers"}
+	}
+
+	compareClients = make([]gocd.GoCd, 0, len(compareProfiles))
+
+	for _, profile := range compareProfiles {
+		profileClient, gateway, err := getProfileClient(cmd, args, profile)
+		if err != nil {
+			closeCompareGateways()
+
+			return err
+		}
+
+		compareClients = append(compareClients, profileClient)
+
+		if gateway != nil {
+			compareGateways = append(compareGateways, gateway)
+		}
+	}
+
+	return setCLIOutput()
+}
+
+// compareResources fetches the resources of the kinds from the servers of all the profiles, and compares them.
+func compareResources(kinds []string) ([]comparedResource, error) {
+	fetchers := getCompareFetchers()
+	contents := make(map[string]map[string]manifest.Document)
+	resources := make([]comparedResource, 0)
+
+	defer func(baseClient gocd.GoCd) {
+		client = baseClient
+	}(client)
+
+	for index, profile := range compareProfiles {
+		client = compareClients[index]
+
+		objects, err := fetchObjects(fetchers, kinds)
+		if err != nil {
+			return nil, fmt.Errorf("fetching resources from profile '%s' errored with: %w", profile, err)
+		}
+
+		for _, object := range objects {
+			selected, err := isNameSelected(compareNames, object.name)
+			if err != nil {
+				return nil, err
+			}
+
+			if !selected {
+				continue
+			}
+
+			document, err := manifest.NewDocument(object.kind, fmt.Sprintf("profile '%s'", profile), object.object,
+				append(manifestOmittedFields[object.kind], compareOmittedFields[object.kind]...)...)
+			if err != nil {
+				return nil, err
+			}
+
+			if err = document.ReplaceSecrets(func(_ string) (string, error) { return compareMaskedSecret, nil }); err != nil {
+				return nil, err
+			}
+
+			key := getManifestKey(object.kind, object.name)
+			if _, ok := contents[key]; !ok {
+				contents[key] = make(map[string]manifest.Document)
+				resources = append(resources, comparedResource{Kind: object.kind, Name: object.name})
+			}
+
+			contents[key][profile] = document
+		}
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return getKindIndex(kinds, resources[i].Kind) < getKindIndex(kinds, resources[j].Kind)
+		}
+
+		return resources[i].Name < resources[j].Name
+	})
+
+	for index := range resources {
+		if err := setCompareStates(&resources[index], contents[getManifestKey(resources[index].Kind, resources[index].Name)]); err != nil {
+			return nil, err
+		}
+	}
+
+	return resources, nil
+}
+
+// setCompareStates sets the state of the resource on every profile, comparing it structurally against the first profile that has it,
+// so that the lists reordered by the servers are not reported as differences.
+func setCompareStates(resource *comparedResource, contents map[string]manifest.Document) error {
+	resource.States = make(map[string]string, len(compareProfiles))
+
+	var reference string
+
+	for _, profile := range compareProfiles {
+		content, ok := contents[profile]
+
+		switch {
+		case !ok:
+			resource.States[profile] = compareStateAbsent
+		case len(reference) == 0:
+			reference = profile
+			resource.States[profile] = compareStatePresent
+		default:
+			changes, err := structdiff.Diff(contents[reference].Object, content.Object)
+			if err != nil {
+				return err
+			}
+
+			if len(changes) == 0 {
+				resource.States[profile] = compareStatePresent
+
+				continue
+			}
+
+			resource.States[profile] = compareStateDiffers
+
+			if compareDiff {
+				if resource.Diffs == nil {
+					resource.Diffs = make(map[string]string)
+				}
+
+				resource.Diffs[profile] = structdiff.String(changes)
+			}
+		}
+	}
+
+	return nil
+}
+
+func renderComparedResources(resources []comparedResource) error {
+	if cliCfg.yaml || cliCfg.json {
+		return cliRenderer.Render(resources)
+	}
+
+	cliCfg.TableData = append(cliCfg.TableData, append([]string{"Kind", "Name"}, compareProfiles...))
+
+	for _, resource := range resources {
+		row := []string{resource.Kind, resource.Name}
+		for _, profile := range compareProfiles {
+			row = append(row, resource.States[profile])
+		}
+
+		cliCfg.TableData = append(cliCfg.TableData, row)
+	}
+
+	if err := cliRenderer.ToTable(cliCfg.TableData); err != nil {
+		return err
+	}
+
+	for _, resource := range resources {
+		for _, profile := range compareProfiles {
+			diffIdentified, ok := resource.Diffs[profile]
+			if !ok {
+				continue
+			}
+
+			fmt.Printf("# %s '%s' on profile '%s' differs from the one on profile '%s'\n%s\n",
+				resource.Kind, resource.Name, profile, getReferenceProfile(resource), diffIdentified)
+		}
+	}
+
+	return nil
+}
+
+// getReferenceProfile returns the first profile that has the resource, the resource on other profiles are compared against it.
+func getReferenceProfile(resource comparedResource) string {
+	for _, profile := range compareProfiles {
+		if resource.States[profile] != compareStateAbsent {
+			return profile
+		}
+	}
+
+	return ""
+}
+
+func isIdentical(resource comparedResource) bool {
+	for _, state := range resource.States {
+		if state != compareStatePresent {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getCompareKinds returns the kinds that could be compared, the kinds of the manifests along with the plugins installed.
+func getCompareKinds() []string {
+	return append(manifest.Kinds(), kindPlugin)
+}
+
+// getCompareFetchers returns the fetchers of all the kinds that could be compared.
+func getCompareFetchers() map[string]manifestFetcher {
+	fetchers := make(map[string]manifestFetcher, len(manifestFetchers)+1)
+	for kind, fetcher := range manifestFetchers {
+		fetchers[kind] = fetcher
+	}
+
+	fetchers[kindPlugin] = func() ([]manifestObject, error) {
+		pluginsInfo, err := client.GetPluginsInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0, len(pluginsInfo.Plugins))
+		for _, plugin := range pluginsInfo.Plugins {
+			objects = append(objects, manifestObject{kind: kindPlugin, name: plugin.ID, object: plugin})
+		}
+
+		return objects, nil
+	}
+
+	return fetchers
+}
+
+func getKindIndex(kinds []string, kind string) int {
+	for index, selectedKind := range kinds {
+		if selectedKind == kind {
+			return index
+		}
+	}
+
+	return len(kinds)
+}
+
+func closeCompareGateways() {
+	for _, gateway := range compareGateways {
+		if err := gateway.Close(); err != nil {
+			cliLogger.Debugf("closing loopback gateway errored with '%v'", err)
+		}
+	}
+
+	compareGateways = nil
+}
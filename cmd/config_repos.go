@@ -1,22 +1,18 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
 	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/thoas/go-funk"
-	"gopkg.in/yaml.v3"
 )
 
 type configRepoPreflight struct {
@@ -236,7 +232,7 @@ gocd-cli configrepo get-definitions --repo-name sample-repo -o yaml --pipelines
 		PreRunE: setCLIClient,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			if !all && len(goCDConfigReposName) == 0 {
-				cliLogger.Fatalf("no config repo name passed, either set --all or pass name using --repo-name")
+				return &errors.ValidationError{Message: "no config repo name passed, either set --all or pass name using --repo-name"}
 			}
 
 			for {
@@ -352,11 +348,12 @@ gocd-cli configrepo get-definitions --repo-name sample-repo -o yaml --pipelines
 
 func getConfigRepoCommand() *cobra.Command {
 	configGetCommand := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET the config-repo information with a specified ID present in GoCD [https://api.gocd.org/current/#get-a-config-repo]",
-		Example: "gocd-cli configrepo get helm-images",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get",
+		Short:             "Command to GET the config-repo information with a specified ID present in GoCD [https://api.gocd.org/current/#get-a-config-repo]",
+		Example:           "gocd-cli configrepo get helm-images",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceConfigRepo),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetConfigRepo(args[0])
@@ -431,17 +428,8 @@ func getUpdateConfigRepoCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &configRepo); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &configRepo); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &configRepo); err != nil {
+				return err
 			}
 
 			configRepoFetched, err := client.GetConfigRepo(configRepo.ID)
@@ -455,22 +443,19 @@ func getUpdateConfigRepoCommand() *cobra.Command {
 				}
 			}
 
-			if len(configRepo.ETAG) == 0 {
-				configRepo.ETAG = configRepoFetched.ETAG
+			if configRepo, err = rebaseResource(configRepoFetched, configRepo); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "config-repo", configRepoFetched.ID)
 
-			existing, err := diffCfg.String(configRepoFetched)
-			if err != nil {
-				return err
-			}
-
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
+			if err = cliCfg.CheckDiffAndAllow(configRepoFetched, configRepo, object.String()); err != nil {
 				return err
 			}
 
-			response, err := client.UpdateConfigRepo(configRepo)
+			response, err := updateResource(configRepoFetched, configRepo, func() (gocd.ConfigRepo, error) {
+				return client.GetConfigRepo(configRepo.ID)
+			}, client.UpdateConfigRepo)
 			if err != nil {
 				return err
 			}
@@ -483,31 +468,25 @@ func getUpdateConfigRepoCommand() *cobra.Command {
 	configRepoUpdateCommand.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a config repo by this name doesn't already exist, run create")
 
+	setUpdateMergeHelp(configRepoUpdateCommand)
+
 	return configRepoUpdateCommand
 }
 
 func getDeleteConfigRepoCommand() *cobra.Command {
 	deleteConfigRepoCommand := &cobra.Command{
-		Use:     "delete",
-		Short:   "Command to DELETE the specified config-repo [https://api.gocd.org/current/#delete-a-config-repo]",
-		Example: "gocd-cli configrepo delete helm-images",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "delete",
+		Short:             "Command to DELETE the specified config-repo [https://api.gocd.org/current/#delete-a-config-repo]",
+		Example:           "gocd-cli configrepo delete helm-images",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceConfigRepo),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			configRepoName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete config repo '%s' [y/n]", configRepoName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteConfigRepo(configRepoName); err != nil {
@@ -561,11 +540,12 @@ func listConfigReposCommand() *cobra.Command {
 
 func getConfigRepoStatusCommand() *cobra.Command {
 	configStatusCommand := &cobra.Command{
-		Use:     "status",
-		Short:   "Command to GET the status of config-repo update operation [https://api.gocd.org/current/#status-of-config-repository-update]",
-		Example: "gocd-cli configrepo status helm-images",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "status",
+		Short:             "Command to GET the status of config-repo update operation [https://api.gocd.org/current/#status-of-config-repository-update]",
+		Example:           "gocd-cli configrepo status helm-images",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceConfigRepo),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.ConfigRepoStatus(args[0])
@@ -595,11 +575,12 @@ func getConfigRepoStatusCommand() *cobra.Command {
 
 func getConfigRepoTriggerUpdateCommand() *cobra.Command {
 	configTriggerUpdateCommand := &cobra.Command{
-		Use:     "trigger-update",
-		Short:   "Command to TRIGGER the update for config-repo to get latest revisions [https://api.gocd.org/current/#trigger-update-of-config-repository]",
-		Example: "gocd-cli configrepo trigger-update helm-images",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "trigger-update",
+		Short:             "Command to TRIGGER the update for config-repo to get latest revisions [https://api.gocd.org/current/#trigger-update-of-config-repository]",
+		Example:           "gocd-cli configrepo trigger-update helm-images",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceConfigRepo),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			response, err := client.ConfigRepoTriggerUpdate(args[0])
 			if err != nil {
@@ -692,17 +673,8 @@ func createConfigRepo(cmd *cobra.Command, _ []string) error {
 
 	fmt.Println(object.String())
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &configRepo); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &configRepo); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &configRepo); err != nil {
+		return err
 	}
 
 	if err = client.CreateConfigRepo(configRepo); err != nil {
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+)
+
+// confirm asks the user to confirm the action with the message set in cliShellReadConfig, unless --yes or --dry-run is set.
+// Opting out is returned as errors.UserDeclinedError, so that the cli exits with its own exit code instead of a success.
+func (cfg *Config) confirm() error {
+	if cfg.Yes || cfg.DryRun {
+		return nil
+	}
+
+	contains, option := cliShellReadConfig.Reader()
+	if !contains {
+		return &errors.ValidationError{Message: inputValidationFailureMessage}
+	}
+
+	if option.Short == "n" {
+		return &errors.UserDeclinedError{Message: optingOutMessage}
+	}
+
+	return nil
+}
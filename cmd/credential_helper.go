@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/credhelper"
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+)
+
+const credentialHelperTimeout = 30 * time.Second
+
+var (
+	goCdCredentialHelper        *credhelper.Helper
+	goCdCredentialHelperRequest credhelper.Request
+	// goCdCredentialHelpers are the helpers run so far by the process, so that the credentials cached in memory by them
+	// are reused by the following commands of the shell session.
+	goCdCredentialHelpers = make(map[string]*credhelper.Helper)
+)
+
+// setAuthFromCredentialHelper fetches the credentials from the credential helper when one is configured,
+// and credentials are not passed explicitly.
+func (cfg *Config) setAuthFromCredentialHelper() error {
+	goCdCredentialHelper = nil
+
+	if len(cfg.CredentialHelper) == 0 || cfg.skipCredHelper || len(cfg.Replay) != 0 {
+		return nil
+	}
+
+	if len(cfg.Auth.BearerToken) != 0 || len(cfg.Auth.Password) != 0 {
+		cliLogger.Debug("credentials are already set, hence not running the credential helper")
+
+		return nil
+	}
+
+	cliLogger.Debugf("fetching credentials using the credential helper '%s'", cfg.CredentialHelper)
+
+	helper := getCredentialHelper(cfg.CredentialHelper, cfg.CacheCredentials)
+	request := credhelper.Request{ServerURL: cfg.URL, Profile: cfg.getProfileName(), UserName: cfg.Auth.UserName}
+
+	response, err := helper.Get(request)
+	if err != nil {
+		return err
+	}
+
+	goCdCredentialHelper, goCdCredentialHelperRequest = helper, request
+
+	if len(response.UserName) != 0 {
+		cfg.Auth.UserName = response.UserName
+	}
+
+	cfg.Auth.Password = response.Password
+	cfg.Auth.BearerToken = response.BearerToken
+
+	cliLogger.Debugf("credentials fetched using the credential helper are valid till %s", response.ExpiresAt.Format(time.RFC3339))
+
+	return nil
+}
+
+// getCredentialHelper returns the helper running the command passed, the one run earlier by the process is reused if any.
+// The credentials are cached on disk under $HOME/.gocd/cache/credentials only when opted with --cache-credentials.
+func getCredentialHelper(command string, cacheOnDisk bool) *credhelper.Helper {
+	var cacheDir string
+
+	if cacheOnDisk {
+		if goCdCacheDir, err := getCacheDir(); err != nil {
+			cliLogger.Warnf("credentials from the credential helper would not be cached on disk, as fetching user's home directory errored with '%v'", err)
+		} else {
+			cacheDir = filepath.Join(goCdCacheDir, goCdCredentialCacheDirName)
+		}
+	}
+
+	key := command + "\x00" + cacheDir
+	if helper, ok := goCdCredentialHelpers[key]; ok {
+		return helper
+	}
+
+	helper := credhelper.NewHelper(command, cacheDir, credentialHelperTimeout)
+	goCdCredentialHelpers[key] = helper
+
+	return helper
+}
+
+// eraseCredentialHelperCache removes the credentials cached from the credential helper when GoCD server rejected them,
+// so that the next command runs the helper again rather than failing with the revoked or expired credentials till they expire.
+func eraseCredentialHelperCache(err error) {
+	if goCdCredentialHelper == nil || errors.StatusCode(err) != http.StatusUnauthorized {
+		return
+	}
+
+	if eraseErr := goCdCredentialHelper.Erase(goCdCredentialHelperRequest); eraseErr != nil {
+		cliLogger.Debugf("erasing the credentials cached from the credential helper errored with '%v'", eraseErr)
+
+		return
+	}
+
+	cliLogger.Debug("credentials cached from the credential helper were rejected by GoCD server, hence were erased")
+}
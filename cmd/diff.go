@@ -1,36 +1,69 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/structdiff"
 )
 
-func (cfg *Config) CheckDiffAndAllow(oldData, newData string) error {
-	hasDiff, diffIdentified, err := diffCfg.Diff(oldData, newData)
+// CheckDiffAndAllow shows the changes to be applied to the resource on GoCD server and asks to confirm them.
+// The resource fetched from GoCD server and the one decoded from the input (newData) are compared structurally,
+// so that only the changes to the fields are shown and not the ones to the order of the keys or the format of the input.
+// With --plan-out the changes are saved as plan instead, and while applying a plan the changes are verified against the planned ones.
+func (cfg *Config) CheckDiffAndAllow(fetched, desired interface{}, newData string) error {
+	oldData, err := getPlanState(fetched)
+	if err != nil {
+		return err
+	}
+
+	if goCdPlan != nil {
+		return verifyPlan(oldData, newData)
+	}
+
+	diffIdentified, err := getDiff(fetched, desired)
 	if err != nil {
 		return err
 	}
 
-	if !hasDiff {
-		cliLogger.Info("no changes to the input file, nothing to update, quitting")
-		os.Exit(0)
+	return cfg.allowDiff(oldData, newData, diffIdentified)
+}
+
+// getDiff is the first phase of CheckDiffAndAllow, that identifies the changes between the resource on GoCD server and the one passed.
+func getDiff(fetched, desired interface{}) (string, error) {
+	changes, err := structdiff.Diff(fetched, desired)
+	if err != nil {
+		return "", err
+	}
+
+	if len(changes) == 0 {
+		return "", &errors.NoChangesError{Message: "no changes to the input file, nothing to update, quitting"}
 	}
 
+	return structdiff.String(changes), nil
+}
+
+// allowDiff is the second phase of CheckDiffAndAllow, that either saves the changes identified as plan or asks to confirm them.
+func (cfg *Config) allowDiff(oldData, newData, diffIdentified string) error {
 	fmt.Printf("%s\n", diffIdentified)
-	fmt.Printf("%s\n\n", "Above changes would be applied")
 
-	if !cfg.Yes {
-		contains, option := cliShellReadConfig.Reader()
-		if !contains {
-			cliLogger.Fatalln(inputValidationFailureMessage)
-		}
+	if len(cfg.PlanOut) != 0 {
+		return cfg.savePlan(oldData, newData, diffIdentified)
+	}
 
-		if option.Short == "n" {
-			cliLogger.Warn(optingOutMessage)
+	fmt.Printf("%s\n\n", "Above changes would be applied")
 
-			os.Exit(0)
-		}
+	return cfg.confirm()
+}
+
+// getPlanState returns the state of the resource fetched as recorded in the plans, it is the compact JSON of it,
+// so that it does not depend on the output format the plan was made or applied with.
+func getPlanState(fetched interface{}) (string, error) {
+	state, err := json.Marshal(fetched)
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	return string(state), nil
 }
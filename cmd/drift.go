@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/spf13/cobra"
+	"github.com/thoas/go-funk"
+)
+
+// States of the resources that drifted from the manifests.
+const (
+	driftStateModified        = "modified"
+	driftStateMissingOnServer = "missing_on_server"
+	driftStateOnlyOnServer    = "only_on_server"
+)
+
+var (
+	driftDir   string
+	driftKinds []string
+)
+
+// driftedResource is a resource on GoCD server that is not in the state declared by the manifests.
+type driftedResource struct {
+	Kind   string `json:"kind" yaml:"kind" csv:"kind"`
+	Name   string `json:"name" yaml:"name" csv:"name"`
+	State  string `json:"state" yaml:"state" csv:"state"`
+	Source string `json:"source,omitempty" yaml:"source,omitempty" csv:"source"`
+	Diff   string `json:"diff,omitempty" yaml:"diff,omitempty" csv:"-"`
+}
+
+func registerDriftCommand() *cobra.Command {
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Command to DETECT the drift between the manifests under a directory and the resources on GoCD server",
+		Long: `Command to DETECT the drift between the manifests under a directory and the resources on GoCD server.
+Every resource declared in the manifests is compared against the one on GoCD server, the resources are reported as:
+  modified           - the resource on GoCD server differs from the manifest
+  missing_on_server  - the resource is declared in the manifests but does not exist on GoCD server
+  only_on_server     - the resource exists on GoCD server but is not declared in the manifests
+
+The resources defined in config repos are not reported as only_on_server, same as 'export'.
+The command exits with code 10 when a drift is identified, so that it could be scheduled to catch the changes made outside the reviewed manifests.`,
+		Example: `gocd-cli drift --dir ./gocd-state
+gocd-cli drift --dir ./gocd-state --kind Pipeline --kind Environment -o json`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCLIClient,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			drifted, err := getDriftedResources()
+			if err != nil {
+				return err
+			}
+
+			if err = renderDriftedResources(drifted); err != nil {
+				return err
+			}
+
+			if len(drifted) != 0 {
+				return &errors.DriftError{Message: fmt.Sprintf("drift identified in %d resource(s) between '%s' and GoCD server", len(drifted), driftDir)}
+			}
+
+			return nil
+		},
+	}
+
+	registerManifestKindFlag(driftCmd, &driftKinds, "kinds of the resources to be checked for drift, defaults to all the kinds supported")
+
+	driftCmd.PersistentFlags().StringVarP(&driftDir, "dir", "", "",
+		"directory of manifests to be compared against GoCD server, ex: the one saved by 'export'")
+
+	if err := driftCmd.MarkPersistentFlagRequired("dir"); err != nil {
+		cliLogger.Fatalf("%v", err)
+	}
+
+	driftCmd.SetUsageTemplate(getUsageTemplate())
+	driftCmd.SilenceUsage = true
+
+	return driftCmd
+}
+
+// getDriftedResources compares the manifests under the directory against the resources on GoCD server, of the kinds selected.
+func getDriftedResources() ([]driftedResource, error) {
+	kinds, err := getManifestKinds(driftKinds)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := manifest.Read(driftDir)
+	if err != nil {
+		return nil, err
+	}
+
+	drifted := make([]driftedResource, 0)
+	declared := make(map[string]bool, len(documents))
+
+	for _, document := range documents {
+		if !funk.Contains(kinds, document.Kind) {
+			continue
+		}
+
+		change, err := manifestPlanners[document.Kind](document)
+		if err != nil {
+			return nil, err
+		}
+
+		declared[getManifestKey(document.Kind, change.name)] = true
+
+		if change.existing == nil {
+			drifted = append(drifted, driftedResource{Kind: document.Kind, Name: change.name, State: driftStateMissingOnServer, Source: document.Source})
+
+			continue
+		}
+
+		diffIdentified, err := change.diff()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(diffIdentified) == 0 {
+			cliLogger.Debugf("%s '%s' from %s has not drifted", document.Kind, change.name, document.Source)
+
+			continue
+		}
+
+		drifted = append(drifted, driftedResource{
+			Kind:   document.Kind,
+			Name:   change.name,
+			State:  driftStateModified,
+			Source: document.Source,
+			Diff:   diffIdentified,
+		})
+	}
+
+	objects, err := fetchManifestObjects(kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range objects {
+		if declared[getManifestKey(object.kind, object.name)] {
+			continue
+		}
+
+		drifted = append(drifted, driftedResource{Kind: object.kind, Name: object.name, State: driftStateOnlyOnServer})
+	}
+
+	return drifted, nil
+}
+
+func renderDriftedResources(drifted []driftedResource) error {
+	switch {
+	case cliCfg.table:
+		cliCfg.TableData = append(cliCfg.TableData, []string{"Kind", "Name", "State", "Source"})
+		for _, resource := range drifted {
+			cliCfg.TableData = append(cliCfg.TableData, []string{resource.Kind, resource.Name, resource.State, resource.Source})
+		}
+
+		return cliRenderer.Render(cliCfg.TableData)
+	case len(cliCfg.OutputFormat) != 0:
+		return cliRenderer.Render(drifted)
+	}
+
+	if len(drifted) == 0 {
+		return cliRenderer.Render(fmt.Sprintf("no drift identified between '%s' and GoCD server", driftDir))
+	}
+
+	for _, resource := range drifted {
+		switch resource.State {
+		case driftStateModified:
+			fmt.Printf("# %s '%s' is modified on GoCD server (%s)\n%s\n", resource.Kind, resource.Name, resource.Source, resource.Diff)
+		case driftStateMissingOnServer:
+			fmt.Printf("# %s '%s' is missing on GoCD server (%s)\n", resource.Kind, resource.Name, resource.Source)
+		default:
+			fmt.Printf("# %s '%s' exists only on GoCD server\n", resource.Kind, resource.Name)
+		}
+	}
+
+	fmt.Println()
+
+	return nil
+}
+
+func getManifestKey(kind, name string) string {
+	return strings.Join([]string{kind, name}, "/")
+}
@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+
+	ghodssYAML "github.com/ghodss/yaml"
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-cli/pkg/structdiff"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultEditor = "vi"
+	editorEnv     = "EDITOR"
+	goCdEditorEnv = "GOCD_EDITOR"
+	editFileMode  = 0o600
+)
+
+// editHeader is added on top of the resource opened in the editor, the lines beginning with '#' are ignored while reading it back.
+const editHeader = `# Please edit the resource below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving, this file will be
+# reopened with the relevant failures.
+#
+`
+
+// resourceGetter fetches the resource of a kind by its name from GoCD server, the name is ignored for the kinds GoCD server has only one of.
+type resourceGetter func(name string) (interface{}, error)
+
+// resourceGetters holds the resourceGetter of every kind supported by 'edit', add an entry here to support a new kind.
+var resourceGetters = map[string]resourceGetter{
+	manifest.KindSiteURL: func(_ string) (interface{}, error) {
+		return client.GetSiteURL()
+	},
+	manifest.KindMailServer: func(_ string) (interface{}, error) {
+		return client.GetMailServerConfig()
+	},
+	manifest.KindJobTimeout: func(_ string) (interface{}, error) {
+		return client.GetDefaultJobTimeout()
+	},
+	manifest.KindBackupConfig: func(_ string) (interface{}, error) {
+		return client.GetBackupConfig()
+	},
+	manifest.KindPluginSettings: func(name string) (interface{}, error) {
+		return client.GetPluginSettings(name)
+	},
+	manifest.KindAuthConfig: func(name string) (interface{}, error) {
+		return client.GetAuthConfig(name)
+	},
+	manifest.KindRole: func(name string) (interface{}, error) {
+		return client.GetRole(name)
+	},
+	manifest.KindClusterProfile: func(name string) (interface{}, error) {
+		return client.GetClusterProfile(name)
+	},
+	manifest.KindElasticAgentProfile: func(name string) (interface{}, error) {
+		return client.GetElasticAgentProfile(name)
+	},
+	manifest.KindArtifactStore: func(name string) (interface{}, error) {
+		return client.GetArtifactStore(name)
+	},
+	manifest.KindConfigRepo: func(name string) (interface{}, error) {
+		return client.GetConfigRepo(name)
+	},
+	manifest.KindPipelineGroup: func(name string) (interface{}, error) {
+		return client.GetPipelineGroup(name)
+	},
+	manifest.KindPipeline: func(name string) (interface{}, error) {
+		return client.GetPipelineConfig(name)
+	},
+	manifest.KindEnvironment: func(name string) (interface{}, error) {
+		return client.GetEnvironment(name)
+	},
+}
+
+// singletonNames are the names of the kinds that GoCD server has only one of, they are edited without passing a name.
+var singletonNames = map[string]string{
+	manifest.KindSiteURL:      siteURLName,
+	manifest.KindMailServer:   mailServerName,
+	manifest.KindJobTimeout:   jobTimeoutName,
+	manifest.KindBackupConfig: backupConfigName,
+}
+
+// editResources are the kinds of resources whose names are completed while editing.
+var editResources = map[string]string{
+	manifest.KindAuthConfig:          resourceAuthConfig,
+	manifest.KindRole:                resourceRole,
+	manifest.KindClusterProfile:      resourceClusterProfile,
+	manifest.KindElasticAgentProfile: resourceElasticAgentProfile,
+	manifest.KindArtifactStore:       resourceArtifactStore,
+	manifest.KindConfigRepo:          resourceConfigRepo,
+	manifest.KindPipelineGroup:       resourcePipelineGroup,
+	manifest.KindPipeline:            resourcePipeline,
+	manifest.KindEnvironment:         resourceEnvironment,
+}
+
+func registerEditCommand() *cobra.Command {
+	editCmd := &cobra.Command{
+		Use:   "edit <kind> [name]",
+		Short: "Command to EDIT a resource of GoCD server in an editor, like 'kubectl edit'",
+		Long: fmt.Sprintf(`Command to EDIT a resource of GoCD server in an editor, like 'kubectl edit'.
+The resource is fetched and opened in the editor set by $GOCD_EDITOR or $EDITOR (defaults to 'vi'), in YAML or in JSON with '-o json'.
+Once saved and closed, the changes are validated and shown as a diff before confirmation, and the resource is updated with the
+etag fetched before editing, so that the changes made by others in the meantime are never overwritten.
+When the update fails, the editor is reopened with the error annotated on top.
+
+Kinds supported are: %s, they could also be passed in lower case with hyphens, ex: elastic-agent-profile.
+The name is not required for the kinds that GoCD server has only one of, ex: SiteURL.`, strings.Join(manifest.Kinds(), ", ")),
+		Example: `gocd-cli edit pipeline sample-pipeline
+gocd-cli edit elastic-agent-profile docker -o json
+EDITOR="code --wait" gocd-cli edit environment production
+gocd-cli edit site-url`,
+		Args:              cobra.RangeArgs(1, 2), //nolint:mnd
+		PreRunE:           setCLIClient,
+		ValidArgsFunction: completeEditArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			kind, err := getEditKind(args[0])
+			if err != nil {
+				return err
+			}
+
+			name, err := getEditName(kind, args[1:])
+			if err != nil {
+				return err
+			}
+
+			return editResource(kind, name)
+		},
+	}
+
+	editCmd.SetUsageTemplate(getUsageTemplate())
+	editCmd.SilenceUsage = true
+
+	return editCmd
+}
+
+// editResource opens the resource in the editor until it is updated, or the edit is aborted or failed in a way that editing again would not fix.
+func editResource(kind, name string) error {
+	fetched, err := resourceGetters[kind](name)
+	if err != nil {
+		return err
+	}
+
+	original, err := getEditContent(kind, fetched)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", fmt.Sprintf("gocd-cli-edit-*%s", getEditExtension()))
+	if err != nil {
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	content := append([]byte(editHeader), original...)
+
+	for {
+		edited, err := openInEditor(file.Name(), content)
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(stripHeader(edited))) == 0 {
+			return &errors.NoChangesError{Message: "edit aborted since the file was emptied"}
+		}
+
+		if bytes.Equal(stripHeader(edited), stripHeader(original)) {
+			return &errors.NoChangesError{Message: fmt.Sprintf("no changes made to %s '%s', edit cancelled", kind, name)}
+		}
+
+		err = updateEditedResource(kind, name, fetched, file.Name())
+		if err == nil {
+			return cliRenderer.Render(fmt.Sprintf("%s %s edited successfully", kind, name))
+		}
+
+		if errors.ExitCode(err) != errors.ExitCodeValidationFailed {
+			return err
+		}
+
+		cliLogger.Errorf("updating %s '%s' errored with: %v, reopening the editor", kind, name, err)
+
+		content = annotateEditError(stripHeader(edited), err)
+	}
+}
+
+// updateEditedResource validates the resource saved in the file, shows the changes and updates it with the etag fetched before editing.
+func updateEditedResource(kind, name string, fetched interface{}, file string) error {
+	documents, err := manifest.Read(file)
+	if err != nil {
+		return err
+	}
+
+	if len(documents) != 1 || documents[0].Kind != kind {
+		return &errors.ValidationError{Message: fmt.Sprintf("the file should hold a single resource of kind '%s'", kind)}
+	}
+
+	change, err := manifestPlanners[kind](documents[0])
+	if err != nil {
+		return err
+	}
+
+	if change.name != name {
+		return &errors.ValidationError{Message: fmt.Sprintf("name of %s cannot be changed from '%s' to '%s' while editing", kind, name, change.name)}
+	}
+
+	if getETag(change.existing) != getETag(fetched) || (change.existing == nil && !reflect.ValueOf(fetched).IsZero()) {
+		return &errors.ConflictError{Message: fmt.Sprintf("%s '%s' was modified on GoCD server since it was opened for editing, edit it again", kind, name)}
+	}
+
+	changes, err := structdiff.Diff(change.existing, change.desired)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		return &errors.NoChangesError{Message: fmt.Sprintf("no changes made to %s '%s', edit cancelled", kind, name)}
+	}
+
+	fmt.Printf("%s\n\n%s\n\n", structdiff.String(changes), "Above changes would be applied")
+
+	cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, strings.ToLower(kind), name)
+
+	if err = cliCfg.confirm(); err != nil {
+		return err
+	}
+
+	return change.apply()
+}
+
+// openInEditor writes the content to the file, opens it in the editor and returns the content saved once the editor is closed.
+func openInEditor(file string, content []byte) ([]byte, error) {
+	if err := os.WriteFile(file, content, editFileMode); err != nil {
+		return nil, err
+	}
+
+	editor, err := splitShellWords(getEditor())
+	if err != nil {
+		return nil, err
+	}
+
+	cliLogger.Debugf("opening '%s' in editor '%s'", file, strings.Join(editor, " "))
+
+	editorCmd := exec.Command(editor[0], append(editor[1:], file)...) //nolint:gosec
+	editorCmd.Stdin, editorCmd.Stdout, editorCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err = editorCmd.Run(); err != nil {
+		return nil, &errors.CLIError{Message: fmt.Sprintf("editor '%s' errored with: %v", strings.Join(editor, " "), err)}
+	}
+
+	return os.ReadFile(file)
+}
+
+// getEditContent returns the resource as it is opened in the editor, in YAML or in JSON when the output format is json.
+func getEditContent(kind string, object interface{}) ([]byte, error) {
+	content, err := manifest.Marshal(kind, object, manifestOmittedFields[kind]...)
+	if err != nil || !cliCfg.json {
+		return content, err
+	}
+
+	if content, err = ghodssYAML.YAMLToJSON(content); err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err = json.Indent(&indented, content, "", "  "); err != nil {
+		return nil, err
+	}
+
+	return append(indented.Bytes(), '\n'), nil
+}
+
+func getEditExtension() string {
+	if cliCfg.json {
+		return ".json"
+	}
+
+	return ".yaml"
+}
+
+func getEditor() string {
+	for _, env := range []string{goCdEditorEnv, editorEnv} {
+		if editor := strings.TrimSpace(os.Getenv(env)); len(editor) != 0 {
+			return editor
+		}
+	}
+
+	return defaultEditor
+}
+
+// getEditKind returns the kind matching the one passed, it could be passed in any case and with hyphens. ex: elastic-agent-profile.
+func getEditKind(kind string) (string, error) {
+	for _, supportedKind := range manifest.Kinds() {
+		if strings.EqualFold(strings.ReplaceAll(kind, "-", ""), supportedKind) {
+			return supportedKind, nil
+		}
+	}
+
+	return "", &errors.ValidationError{Message: fmt.Sprintf("kind '%s' is not supported, it should be one of %s", kind, strings.Join(manifest.Kinds(), "|"))}
+}
+
+func getEditName(kind string, args []string) (string, error) {
+	if singletonName, ok := singletonNames[kind]; ok {
+		if len(args) != 0 {
+			return "", &errors.ValidationError{Message: fmt.Sprintf("%s does not take a name, GoCD server has only one of it", kind)}
+		}
+
+		return singletonName, nil
+	}
+
+	if len(args) == 0 {
+		return "", &errors.ValidationError{Message: fmt.Sprintf("name of the %s to be edited should be passed", kind)}
+	}
+
+	return args[0], nil
+}
+
+// getETag returns the etag of the resource fetched from GoCD server, it is empty for the resources without one.
+func getETag(object interface{}) string {
+	value := reflect.Indirect(reflect.ValueOf(object))
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+
+	if etag := value.FieldByName("ETAG"); etag.IsValid() && etag.Kind() == reflect.String {
+		return etag.String()
+	}
+
+	return ""
+}
+
+// stripHeader drops the lines beginning with '#' on top of the resource, that are added as help and errors.
+// The comments within the resource are left as they are, since they could be a part of multi-line values like scripts.
+func stripHeader(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+
+	for index, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			return []byte(strings.Join(lines[index:], "\n"))
+		}
+	}
+
+	return nil
+}
+
+func annotateEditError(content []byte, err error) []byte {
+	var annotated strings.Builder
+
+	annotated.WriteString(editHeader)
+
+	for _, line := range strings.Split(err.Error(), "\n") {
+		annotated.WriteString(fmt.Sprintf("# error: %s\n", line))
+	}
+
+	annotated.WriteString("#\n")
+	annotated.Write(content)
+
+	return []byte(annotated.String())
+}
+
+// completeEditArgs completes the kind, and the name of the resource for the kinds whose names could be listed.
+func completeEditArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return filterByPrefix(manifest.Kinds(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	kind, err := getEditKind(args[0])
+	if err != nil || len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	resource, ok := editResources[kind]
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeResourceNames(resource)(cmd, args, toComplete)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const goCdEnvPrefix = "GOCD_"
+
+// setFlagsFromEnv sets the global flags that are not passed explicitly, from their GOCD_* environment variables.
+// ex: --server-url can be set using GOCD_SERVER_URL, and --auth-token using GOCD_AUTH_TOKEN.
+// It returns the names of the flags that were set either explicitly or from the environment,
+// so that cached configurations do not override them, making the precedence flag > env > cached profile > default.
+func setFlagsFromEnv(cmd *cobra.Command) (map[string]bool, error) {
+	configuredFlags := make(map[string]bool)
+	if cmd == nil {
+		return configuredFlags, nil
+	}
+
+	var flagErr error
+
+	cmd.Root().PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			configuredFlags[flag.Name] = true
+
+			return
+		}
+
+		envName := getFlagEnvName(flag.Name)
+
+		value, ok := os.LookupEnv(envName)
+		if !ok {
+			return
+		}
+
+		if err := flag.Value.Set(value); err != nil && flagErr == nil {
+			flagErr = &errors.CLIError{Message: fmt.Sprintf("invalid value '%s' set for '%s' via environment variable '%s': %v", value, flag.Name, envName, err)}
+
+			return
+		}
+
+		configuredFlags[flag.Name] = true
+	})
+
+	return configuredFlags, flagErr
+}
+
+func getFlagEnvName(flagName string) string {
+	return goCdEnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
@@ -1,19 +1,14 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
 	"github.com/thoas/go-funk"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -132,8 +127,9 @@ func getEnvironmentCommand() *cobra.Command {
 		Example: `gocd-cli environment get gocd_environment_1
 gocd-cli environment get gocd_environment_1 --env-var ENVIRONMENT_VAR_1 --env-var ENVIRONMENT_VAR_2 -o yaml
 gocd-cli environment get gocd_environment_1 --pipelines -o yaml`,
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceEnvironment),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			response, err := client.GetEnvironment(args[0])
 			if err != nil {
@@ -259,17 +255,8 @@ func updateEnvironmentCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &envs); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &envs); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &envs); err != nil {
+				return err
 			}
 
 			environmentFetched, err := client.GetEnvironment(envs.Name)
@@ -283,22 +270,19 @@ func updateEnvironmentCommand() *cobra.Command {
 				}
 			}
 
-			if len(envs.ETAG) == 0 {
-				envs.ETAG = environmentFetched.ETAG
+			if envs, err = rebaseResource(environmentFetched, envs); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "environment", envs.Name)
 
-			existing, err := diffCfg.String(environmentFetched)
-			if err != nil {
+			if err = cliCfg.CheckDiffAndAllow(environmentFetched, envs, object.String()); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
-				return err
-			}
-
-			env, err := client.UpdateEnvironment(envs)
+			env, err := updateResource(environmentFetched, envs, func() (gocd.Environment, error) {
+				return client.GetEnvironment(envs.Name)
+			}, client.UpdateEnvironment)
 			if err != nil {
 				return err
 			}
@@ -314,6 +298,8 @@ func updateEnvironmentCommand() *cobra.Command {
 	updateEnvironmentCmd.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a environment by this name doesn't already exist, run create")
 
+	setUpdateMergeHelp(updateEnvironmentCmd)
+
 	return updateEnvironmentCmd
 }
 
@@ -331,32 +317,14 @@ func patchEnvironmentCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &envs); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &envs); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &envs); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(patchMessage, "environment", envs.Name)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err = cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			env, err := client.PatchEnvironment(envs)
@@ -377,27 +345,19 @@ func patchEnvironmentCommand() *cobra.Command {
 
 func deleteEnvironmentCommand() *cobra.Command {
 	deleteEnvironmentCmd := &cobra.Command{
-		Use:     "delete",
-		Short:   "Command to DELETE the specified environment from GoCD [https://api.gocd.org/current/#delete-an-environment]",
-		Example: `gocd-cli environment delete gocd_environment_1`,
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "delete",
+		Short:             "Command to DELETE the specified environment from GoCD [https://api.gocd.org/current/#delete-an-environment]",
+		Example:           `gocd-cli environment delete gocd_environment_1`,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceEnvironment),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			environmentName := args[0]
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete gocd environment '%s' [y/n]", environmentName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteEnvironment(environmentName); err != nil {
@@ -477,17 +437,8 @@ func createEnvironment(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &envs); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &envs); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &envs); err != nil {
+		return err
 	}
 
 	if err = client.CreateEnvironment(envs); err != nil {
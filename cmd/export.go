@@ -0,0 +1,464 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+const (
+	originTypeGoCD = "gocd"
+	exportDirMode  = 0o755
+	exportFileMode = 0o644
+)
+
+// Changes made to the manifests of the resources by exportManifests.
+const (
+	exportChangeAdded    = "added"
+	exportChangeModified = "modified"
+	exportChangeRemoved  = "removed"
+)
+
+var (
+	exportDir   string
+	exportKinds []string
+)
+
+// manifestObject is a resource fetched from GoCD server, that could be saved as a manifest.
+type manifestObject struct {
+	kind   string
+	name   string
+	object interface{}
+}
+
+// exportedResource is a resource whose manifest was changed by exportManifests.
+type exportedResource struct {
+	Kind   string `json:"kind" yaml:"kind"`
+	Name   string `json:"name" yaml:"name"`
+	Change string `json:"change" yaml:"change"`
+}
+
+// manifestFetcher fetches all the resources of a kind from GoCD server, that are managed through GoCD (not the ones defined in config repos).
+type manifestFetcher func() ([]manifestObject, error)
+
+// manifestFetchers holds the manifestFetcher of every kind supported by 'export', add an entry here to support a new kind.
+var manifestFetchers = map[string]manifestFetcher{
+	manifest.KindSiteURL: func() ([]manifestObject, error) {
+		siteURL, err := client.GetSiteURL()
+
+		return getSingletonObjects(manifest.KindSiteURL, siteURLName, siteURL, err)
+	},
+	manifest.KindMailServer: func() ([]manifestObject, error) {
+		mailServer, err := client.GetMailServerConfig()
+
+		return getSingletonObjects(manifest.KindMailServer, mailServerName, mailServer, err)
+	},
+	manifest.KindJobTimeout: func() ([]manifestObject, error) {
+		jobTimeout, err := client.GetDefaultJobTimeout()
+
+		return getSingletonObjects(manifest.KindJobTimeout, jobTimeoutName, jobTimeout, err)
+	},
+	manifest.KindBackupConfig: func() ([]manifestObject, error) {
+		backupConfig, err := client.GetBackupConfig()
+
+		return getSingletonObjects(manifest.KindBackupConfig, backupConfigName, backupConfig, err)
+	},
+	manifest.KindPluginSettings: func() ([]manifestObject, error) {
+		pluginsInfo, err := client.GetPluginsInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0)
+
+		for _, plugin := range pluginsInfo.Plugins {
+			setting, err := client.GetPluginSettings(plugin.ID)
+			if err != nil {
+				if isNotFound(err) {
+					continue
+				}
+
+				return nil, err
+			}
+
+			objects = append(objects, manifestObject{kind: manifest.KindPluginSettings, name: plugin.ID, object: setting})
+		}
+
+		return objects, nil
+	},
+	manifest.KindAuthConfig: func() ([]manifestObject, error) {
+		authConfigs, err := client.GetAuthConfigs()
+		if err != nil {
+			return nil, err
+		}
+
+		return getCommonConfigObjects(manifest.KindAuthConfig, authConfigs), nil
+	},
+	manifest.KindRole: func() ([]manifestObject, error) {
+		roles, err := client.GetRoles()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0, len(roles.Role))
+		for _, role := range roles.Role {
+			objects = append(objects, manifestObject{kind: manifest.KindRole, name: role.Name, object: role})
+		}
+
+		return objects, nil
+	},
+	manifest.KindClusterProfile: func() ([]manifestObject, error) {
+		clusterProfiles, err := client.GetClusterProfiles()
+		if err != nil {
+			return nil, err
+		}
+
+		return getCommonConfigObjects(manifest.KindClusterProfile, clusterProfiles.ClusterProfilesConfig), nil
+	},
+	manifest.KindElasticAgentProfile: func() ([]manifestObject, error) {
+		elasticAgentProfiles, err := client.GetElasticAgentProfiles()
+		if err != nil {
+			return nil, err
+		}
+
+		return getCommonConfigObjects(manifest.KindElasticAgentProfile, elasticAgentProfiles.CommonConfigs), nil
+	},
+	manifest.KindArtifactStore: func() ([]manifestObject, error) {
+		artifactStores, err := client.GetArtifactStores()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0, len(artifactStores.CommonConfigs))
+		for _, artifactStore := range artifactStores.CommonConfigs {
+			objects = append(objects, manifestObject{kind: manifest.KindArtifactStore, name: getArtifactStoreID(artifactStore), object: artifactStore})
+		}
+
+		return objects, nil
+	},
+	manifest.KindConfigRepo: func() ([]manifestObject, error) {
+		configRepos, err := client.GetConfigRepos()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0, len(configRepos))
+		for _, configRepo := range configRepos {
+			objects = append(objects, manifestObject{kind: manifest.KindConfigRepo, name: configRepo.ID, object: configRepo})
+		}
+
+		return objects, nil
+	},
+	manifest.KindPipelineGroup: func() ([]manifestObject, error) {
+		pipelineGroups, err := client.GetPipelineGroups()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0, len(pipelineGroups))
+		for _, pipelineGroup := range pipelineGroups {
+			objects = append(objects, manifestObject{kind: manifest.KindPipelineGroup, name: pipelineGroup.Name, object: pipelineGroup})
+		}
+
+		return objects, nil
+	},
+	manifest.KindPipeline: func() ([]manifestObject, error) {
+		pipelines, err := client.GetPipelines()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0)
+
+		for _, pipeline := range pipelines.Pipeline {
+			pipelineConfig, err := client.GetPipelineConfig(pipeline.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if pipelineConfig.Origin.Type != originTypeGoCD {
+				cliLogger.Debugf("skipping pipeline '%s' since it is defined in config repo '%s'", pipeline.Name, pipelineConfig.Origin.ID)
+
+				continue
+			}
+
+			objects = append(objects, manifestObject{kind: manifest.KindPipeline, name: pipeline.Name, object: pipelineConfig})
+		}
+
+		return objects, nil
+	},
+	manifest.KindEnvironment: func() ([]manifestObject, error) {
+		environments, err := client.GetEnvironments()
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]manifestObject, 0, len(environments))
+
+		for _, environment := range environments {
+			if !isDefinedInGoCD(environment.Origins) {
+				cliLogger.Debugf("skipping environment '%s' since it is defined in config repos", environment.Name)
+
+				continue
+			}
+
+			objects = append(objects, manifestObject{kind: manifest.KindEnvironment, name: environment.Name, object: environment})
+		}
+
+		return objects, nil
+	},
+}
+
+func registerExportCommand() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Command to EXPORT all the resources managed through GoCD server, as manifests under a directory",
+		Long: fmt.Sprintf(`Command to EXPORT all the resources managed through GoCD server, as manifests under a directory.
+Every resource is saved to its own file in a stable layout, ex: environments/production.yaml and server/site-url.yaml,
+so that the configuration of GoCD could be reviewed in git. The resources defined in config repos are not exported.
+
+The files saved are readable by 'apply -f <dir>', as well as by the create/update commands of the resources via --from-file.
+Files of the resources that no longer exist on GoCD server are removed. Kinds exported are: %s.`, strings.Join(manifest.Kinds(), ", ")),
+		Example: `gocd-cli export --dir ./gocd-state
+gocd-cli export --dir ./gocd-state --kind Environment --kind Role`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCLIClient,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			objects, err := fetchManifestObjects(exportKinds)
+			if err != nil {
+				return err
+			}
+
+			if _, err = exportManifests(exportDir, exportKinds, objects); err != nil {
+				return err
+			}
+
+			return cliRenderer.Render(fmt.Sprintf("%d resource(s) exported to '%s'", len(objects), exportDir))
+		},
+	}
+
+	registerManifestKindFlag(exportCmd, &exportKinds, "kinds of the resources to be exported, defaults to all the kinds supported")
+
+	exportCmd.PersistentFlags().StringVarP(&exportDir, "dir", "", "",
+		"directory under which the resources are exported")
+
+	if err := exportCmd.MarkPersistentFlagRequired("dir"); err != nil {
+		cliLogger.Fatalf("%v", err)
+	}
+
+	exportCmd.SetUsageTemplate(getUsageTemplate())
+	exportCmd.SilenceUsage = true
+
+	return exportCmd
+}
+
+// fetchManifestObjects fetches the resources of the kinds passed from GoCD server, sorted by kind and name. All the kinds are fetched when none is passed.
+func fetchManifestObjects(kinds []string) ([]manifestObject, error) {
+	kinds, err := getManifestKinds(kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchObjects(manifestFetchers, kinds)
+}
+
+// fetchObjects fetches the resources of the kinds passed from GoCD server using the fetchers, sorted by kind and name.
+func fetchObjects(fetchers map[string]manifestFetcher, kinds []string) ([]manifestObject, error) {
+	objects := make([]manifestObject, 0)
+
+	for _, kind := range kinds {
+		cliLogger.Debugf("fetching resources of kind '%s'", kind)
+
+		kindObjects, err := fetchers[kind]()
+		if err != nil {
+			return nil, fmt.Errorf("fetching resources of kind '%s' errored with: %w", kind, err)
+		}
+
+		sort.SliceStable(kindObjects, func(i, j int) bool {
+			return kindObjects[i].name < kindObjects[j].name
+		})
+
+		objects = append(objects, kindObjects...)
+	}
+
+	return objects, nil
+}
+
+// getManifestKinds validates the kinds passed and returns them in the order they are applied, all the kinds are returned when none is passed.
+func getManifestKinds(kinds []string) ([]string, error) {
+	return selectKinds(manifest.Kinds(), kinds)
+}
+
+// selectKinds validates the kinds passed against the ones supported, and returns them in the order of the supported kinds.
+// The kinds are matched case-insensitively, all the kinds supported are returned when none is passed.
+func selectKinds(supported, kinds []string) ([]string, error) {
+	if len(kinds) == 0 {
+		return supported, nil
+	}
+
+	selected := make([]string, 0, len(kinds))
+
+	for _, kind := range supported {
+		for _, selectedKind := range kinds {
+			if strings.EqualFold(kind, selectedKind) {
+				selected = append(selected, kind)
+
+				break
+			}
+		}
+	}
+
+	if len(selected) != len(kinds) {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("kinds '%s' are not all supported, kinds should be one of %s",
+			strings.Join(kinds, ","), strings.Join(supported, "|"))}
+	}
+
+	return selected, nil
+}
+
+func registerManifestKindFlag(cmd *cobra.Command, kinds *[]string, usage string) {
+	registerKindFlag(cmd, kinds, manifest.Kinds(), usage)
+}
+
+func registerKindFlag(cmd *cobra.Command, kinds *[]string, supported []string, usage string) {
+	cmd.PersistentFlags().StringSliceVarP(kinds, "kind", "", nil, usage)
+
+	registerFlagCompletion(cmd, "kind", func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return filterByPrefix(supported, toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// exportManifests saves the resources as manifests under the directory, and removes the manifests of the resources of the kinds
+// that no longer exist on GoCD server. It returns the resources whose manifests were added, modified or removed.
+func exportManifests(dir string, kinds []string, objects []manifestObject) ([]exportedResource, error) {
+	changed := make([]exportedResource, 0)
+	exported := make(map[string]bool, len(objects))
+
+	for _, object := range objects {
+		data, err := manifest.Marshal(object.kind, object.object, manifestOmittedFields[object.kind]...)
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, manifest.Path(object.kind, object.name))
+		exported[path] = true
+
+		existing, err := os.ReadFile(path)
+
+		switch {
+		case err == nil && bytes.Equal(existing, data):
+			continue
+		case err == nil:
+			changed = append(changed, exportedResource{Kind: object.kind, Name: object.name, Change: exportChangeModified})
+		case os.IsNotExist(err):
+			changed = append(changed, exportedResource{Kind: object.kind, Name: object.name, Change: exportChangeAdded})
+		default:
+			return nil, err
+		}
+
+		if err = os.MkdirAll(filepath.Dir(path), exportDirMode); err != nil {
+			return nil, err
+		}
+
+		if err = os.WriteFile(path, data, exportFileMode); err != nil {
+			return nil, err
+		}
+	}
+
+	removed, err := pruneExportDir(dir, kinds, exported)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changed, removed...), nil
+}
+
+// pruneExportDir removes the manifests under the directories of the kinds exported, that were not exported this time.
+func pruneExportDir(dir string, kinds []string, exported map[string]bool) ([]exportedResource, error) {
+	kinds, err := getManifestKinds(kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]exportedResource, 0)
+
+	for _, kind := range kinds {
+		files, err := filepath.Glob(filepath.Join(dir, manifest.Dir(kind), "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if exported[file] || !isManifestOfKind(file, kind) {
+				continue
+			}
+
+			cliLogger.Debugf("removing '%s' since the resource no longer exists on GoCD server", file)
+
+			if err = os.Remove(file); err != nil {
+				return nil, err
+			}
+
+			removed = append(removed, exportedResource{Kind: kind, Name: strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), Change: exportChangeRemoved})
+		}
+	}
+
+	return removed, nil
+}
+
+// isManifestOfKind returns true if the file holds a single manifest of the kind passed, the directories are shared by the kinds
+// that GoCD has only one of, ex: site URL and mail server. Files that are not manifests are left untouched.
+func isManifestOfKind(file, kind string) bool {
+	documents, err := manifest.Read(file)
+
+	return err == nil && len(documents) == 1 && documents[0].Kind == kind
+}
+
+func getSingletonObjects(kind, name string, object interface{}, err error) ([]manifestObject, error) {
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if reflect.ValueOf(object).IsZero() {
+		return nil, nil
+	}
+
+	return []manifestObject{{kind: kind, name: name, object: object}}, nil
+}
+
+func getCommonConfigObjects(kind string, commonConfigs []gocd.CommonConfig) []manifestObject {
+	objects := make([]manifestObject, 0, len(commonConfigs))
+	for _, commonConfig := range commonConfigs {
+		objects = append(objects, manifestObject{kind: kind, name: commonConfig.ID, object: commonConfig})
+	}
+
+	return objects
+}
+
+// isDefinedInGoCD returns true if the resource is defined through GoCD, and not only in config repos.
+func isDefinedInGoCD(origins []gocd.EnvironmentOrigin) bool {
+	if len(origins) == 0 {
+		return true
+	}
+
+	for _, origin := range origins {
+		if origin.Type == originTypeGoCD {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/extension"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/thoas/go-funk"
+)
+
+const goCdPluginsDirName = "plugins"
+
+// extensionListingCommands are the commands listing the subcommands, ex: help and shell completion.
+// All the extensions are registered for them, so that they show up along with the commands gocd-cli has.
+var extensionListingCommands = []string{"", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// registerExtensionCommands registers the executables named gocd-cli-<name> found under $HOME/.gocd/plugins or PATH as subcommands of the root command.
+// All of them are registered when the args invoke the help or the shell completion, otherwise only the one invoked by the args is looked up
+// when it is not the command gocd-cli already has, rather than listing the directories on every invocation.
+func registerExtensionCommands(rootCmd *cobra.Command, args []string) {
+	name := getCommandName(rootCmd.PersistentFlags(), args)
+
+	if funk.ContainsString(extensionListingCommands, name) {
+		for _, ext := range extension.Discover(getExtensionDirs()...) {
+			addExtensionCommand(rootCmd, ext)
+		}
+
+		return
+	}
+
+	if strings.HasPrefix(name, "__") {
+		return
+	}
+
+	if ext, found := extension.Find(name, getExtensionDirs()...); found {
+		addExtensionCommand(rootCmd, ext)
+	}
+}
+
+// addExtensionCommand adds the extension as subcommand of the root command, unless gocd-cli already has a command with its name.
+func addExtensionCommand(rootCmd *cobra.Command, ext extension.Extension) {
+	if existing, _, err := rootCmd.Find([]string{ext.Name}); err == nil && existing != rootCmd {
+		return
+	}
+
+	rootCmd.AddCommand(getExtensionCommand(ext))
+}
+
+// getExtensionDirs returns the directories the extensions are looked up under, $HOME/.gocd/plugins takes precedence over PATH.
+func getExtensionDirs() []string {
+	dirs := filepath.SplitList(os.Getenv("PATH"))
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append([]string{filepath.Join(home, goCdCacheDirName, goCdPluginsDirName)}, dirs...)
+	}
+
+	return dirs
+}
+
+// getCommandName returns the first of the args that is neither a global flag nor its value, which is the name of the command invoked.
+func getCommandName(flags *pflag.FlagSet, args []string) string {
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+
+		switch {
+		case arg == "--":
+			return ""
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+			if flag := flags.Lookup(name); flag != nil && !hasValue && len(flag.NoOptDefVal) == 0 {
+				index++
+			}
+		case strings.HasPrefix(arg, "-"):
+			// the shorthands with their values attached, ex: -ojson, are not followed by the values.
+			if len(arg) != 2 { //nolint:mnd
+				continue
+			}
+
+			if flag := flags.ShorthandLookup(arg[1:]); flag != nil && len(flag.NoOptDefVal) == 0 {
+				index++
+			}
+		default:
+			return arg
+		}
+	}
+
+	return ""
+}
+
+func getExtensionCommand(ext extension.Extension) *cobra.Command {
+	extensionCommand := &cobra.Command{
+		Use:   ext.Name,
+		Short: fmt.Sprintf("External command provided by '%s'", ext.Path),
+		Long: fmt.Sprintf(`External command provided by '%s'.
+
+The global flags passed in long form (ex: --profile, --output) are consumed by gocd-cli, all other args are passed to the command as is.
+The resolved server URL, credentials and output format are passed to the command as GOCD_* environment variables,
+ex: GOCD_SERVER_URL, GOCD_AUTH_TOKEN and GOCD_OUTPUT.`, ext.Path),
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			extensionArgs, err := splitGlobalFlags(cmd.Root().PersistentFlags(), args)
+			if err != nil {
+				return err
+			}
+
+			if err = setCLIClient(cmd, nil); err != nil {
+				return err
+			}
+
+			cliLogger.Debugf("invoking external command '%s' with args '%s'", ext.Path, strings.Join(extensionArgs, " "))
+
+			return ext.Run(extensionArgs, cliCfg.getExtensionEnv())
+		},
+	}
+	extensionCommand.SilenceUsage = true
+
+	return extensionCommand
+}
+
+// splitGlobalFlags sets the global flags found in args and returns rest of the args.
+// Only the long form of the flags is considered, short forms like -o could as well be the flags of the external command.
+func splitGlobalFlags(flags *pflag.FlagSet, args []string) ([]string, error) {
+	remainingArgs := make([]string, 0, len(args))
+
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		if arg == "--" {
+			remainingArgs = append(remainingArgs, args[index+1:]...)
+
+			break
+		}
+
+		if !strings.HasPrefix(arg, "--") {
+			remainingArgs = append(remainingArgs, arg)
+
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+
+		flag := flags.Lookup(name)
+		if flag == nil {
+			remainingArgs = append(remainingArgs, arg)
+
+			continue
+		}
+
+		if !hasValue {
+			switch {
+			case len(flag.NoOptDefVal) != 0:
+				value = flag.NoOptDefVal
+			case index+1 < len(args):
+				index++
+				value = args[index]
+			default:
+				return nil, &errors.ValidationError{Message: fmt.Sprintf("flag needs an argument: --%s", name)}
+			}
+		}
+
+		if err := flags.Set(flag.Name, value); err != nil {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("invalid argument '%s' for '--%s' flag: %v", value, name, err)}
+		}
+	}
+
+	return remainingArgs, nil
+}
+
+// getExtensionEnv returns the resolved configurations as GOCD_* environment variables,
+// the same variables gocd-cli reads the global flags from, so the external commands invoking gocd-cli share the profile.
+func (cfg *Config) getExtensionEnv() []string {
+	values := map[string]string{
+		"server-url":           cfg.URL,
+		"username":             cfg.Auth.UserName,
+		"password":             cfg.Auth.Password,
+		"auth-token":           cfg.Auth.BearerToken,
+		"no-auth":              strconv.FormatBool(cfg.Auth.NoAuth),
+		"profile":              cfg.getProfileName(),
+		"ca-file-path":         cfg.CaPath,
+		"client-cert-path":     cfg.ClientCertPath,
+		"client-key-path":      cfg.ClientKeyPath,
+		"insecure-skip-verify": strconv.FormatBool(cfg.InsecureSkip),
+		"proxy-url":            cfg.ProxyURL,
+		"no-proxy":             cfg.NoProxy,
+		"output":               cfg.OutputFormat,
+		"log-level":            cfg.LogLevel,
+		"no-color":             strconv.FormatBool(cfg.NoColor),
+		"yes":                  strconv.FormatBool(cfg.Yes),
+		"no-cache":             strconv.FormatBool(cfg.NoCache),
+		"no-history":           strconv.FormatBool(cfg.NoHistory),
+		"save-versions":        strconv.FormatBool(cfg.SaveVersions),
+		"cache-credentials":    strconv.FormatBool(cfg.CacheCredentials),
+		"dry-run":              strconv.FormatBool(cfg.DryRun),
+	}
+
+	if cfg.CacheTTL > 0 {
+		values["cache-ttl"] = cfg.CacheTTL.String()
+	}
+
+	env := make([]string, 0, len(values))
+	for flagName, value := range values {
+		if len(value) == 0 {
+			continue
+		}
+
+		env = append(env, fmt.Sprintf("%s=%s", getFlagEnvName(flagName), value))
+	}
+
+	return env
+}
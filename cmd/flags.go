@@ -30,12 +30,26 @@ func registerGlobalFlags(cmd *cobra.Command) {
 		"password to authenticate with GoCD server")
 	cmd.PersistentFlags().StringVarP(&cliCfg.Auth.BearerToken, "auth-token", "t", "",
 		"token to authenticate with GoCD server, should not be co-used with basic auth (username/password)")
+	cmd.PersistentFlags().StringVarP(&cliCfg.CredentialHelper, "credential-helper", "", "",
+		"external command to fetch the credentials from, it is passed server_url/profile/username as JSON over stdin "+
+			"and should print username/password or bearer_token along with optional expires_at as JSON to stdout")
 	cmd.PersistentFlags().BoolVarP(&cliCfg.Auth.NoAuth, "no-auth", "", false,
 		"enabling this will disable authentication when connecting to the GoCD server")
-	cmd.PersistentFlags().StringVarP(&cliCfg.Profile, "profile", "", "default",
-		"set the profile when managing multiple GoCD, ex: default, central etc")
+	cmd.PersistentFlags().StringVarP(&cliCfg.Profile, "profile", "", "",
+		"set the profile (context) when managing multiple GoCD, ex: default, central etc. "+
+			"if not set, current-context from $HOME/.gocd/contexts.yaml would be used, which defaults to 'default'")
 	cmd.PersistentFlags().StringVarP(&cliCfg.CaPath, "ca-file-path", "", "",
 		"path to file containing CA cert used to authenticate GoCD server, if you have one")
+	cmd.PersistentFlags().StringVarP(&cliCfg.ClientCertPath, "client-cert-path", "", "",
+		"path to file containing client certificate, to be used when GoCD server is behind mutual TLS")
+	cmd.PersistentFlags().StringVarP(&cliCfg.ClientKeyPath, "client-key-path", "", "",
+		"path to file containing private key of the client certificate set by --client-cert-path")
+	cmd.PersistentFlags().BoolVarP(&cliCfg.InsecureSkip, "insecure-skip-verify", "", false,
+		"enabling this will skip verifying the certificate of GoCD server, use it only with lab servers")
+	cmd.PersistentFlags().StringVarP(&cliCfg.ProxyURL, "proxy-url", "", "",
+		"URL of the proxy to be used when connecting to GoCD server, defaults to HTTPS_PROXY/HTTP_PROXY from environment")
+	cmd.PersistentFlags().StringVarP(&cliCfg.NoProxy, "no-proxy", "", "",
+		"comma separated list of hosts for which proxy should not be used, defaults to NO_PROXY from environment")
 	cmd.PersistentFlags().StringVarP(&cliCfg.LogLevel, "log-level", "l", "info",
 		"log level for GoCD cli, log levels supported by [https://github.com/sirupsen/logrus] will work")
 	cmd.PersistentFlags().StringVarP(&cliCfg.APILogLevel, "api-log-level", "", "info",
@@ -52,7 +66,7 @@ func registerGlobalFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&cliCfg.NoColor, "no-color", "", false,
 		"enable this to Render output with no color")
 	cmd.PersistentFlags().BoolVarP(&cliCfg.skipCacheConfig, "skip-cache-config", "", false,
-		"if enabled locally save auth configs would not be used to authenticate GoCD server (path: $HOME/.gocd/auth_config.yaml)")
+		"if enabled locally save auth configs would not be used to authenticate GoCD server (path: $HOME/.gocd/contexts.yaml)")
 	cmd.PersistentFlags().StringVarP(&cliCfg.FromFile, "from-file", "", "",
 		"file containing configurations of objects that needs to be created in GoCD, config-repo/pipeline-group/environment and etc.")
 	cmd.PersistentFlags().StringVarP(&cliCfg.ToFile, "to-file", "", "",
@@ -64,8 +78,29 @@ more queries can be found here https://github.com/thedevsaddam/gojsonq/wiki/Quer
 		"enable this to monitor resources continuously, applicable only if supported by the command")
 	cmd.PersistentFlags().DurationVarP(&cliCfg.WatchInterval, "watch-interval", "", defaultWatchInterval*time.Second,
 		"time interval between each watch cycle")
+	cmd.PersistentFlags().StringVarP(&cliCfg.Record, "record", "", "",
+		"directory under which every call made to GoCD server would be saved as fixture files, with credentials and secrets redacted")
+	cmd.PersistentFlags().StringVarP(&cliCfg.Replay, "replay", "", "",
+		"directory containing the fixtures saved by --record, calls would be served from them instead of calling GoCD server")
+
+	cmd.PersistentFlags().DurationVarP(&cliCfg.CacheTTL, "cache-ttl", "", 0,
+		"when set, successful GET calls to GoCD server are cached under $HOME/.gocd/cache/responses for the duration set, ex: 30s, 5m")
+	cmd.PersistentFlags().BoolVarP(&cliCfg.NoCache, "no-cache", "", false,
+		"enable this to bypass the cache of GET calls, even if --cache-ttl is set")
+	cmd.PersistentFlags().BoolVarP(&cliCfg.NoHistory, "no-history", "", false,
+		"enable this to not save the resources under $HOME/.gocd/history before modifying them, they could not be rolled back then")
+	cmd.PersistentFlags().BoolVarP(&cliCfg.SaveVersions, "save-versions", "", false,
+		"enable this to save the resources fetched under $HOME/.gocd/cache/versions, so that the updates based on them could be merged with the changes made since")
+	cmd.PersistentFlags().BoolVarP(&cliCfg.CacheCredentials, "cache-credentials", "", false,
+		"enable this to cache the credentials fetched by --credential-helper under $HOME/.gocd/cache/credentials till they expire, "+
+			"they are otherwise cached only in memory for the command or the shell session")
+	cmd.PersistentFlags().BoolVarP(&cliCfg.DryRun, "dry-run", "", false,
+		"when enabled, calls that would modify GoCD server (create/update/delete and etc.) are printed instead of being sent, along with the diff if any")
+	cmd.PersistentFlags().StringVarP(&cliCfg.PlanOut, "plan-out", "", "",
+		"file to which the changes identified by the update commands are saved as plan instead of applying them, the plan could be applied later using 'apply-plan'")
 
 	cmd.MarkFlagsMutuallyExclusive("watch", "to-file", "watch-interval")
+	cmd.MarkFlagsMutuallyExclusive("record", "replay")
 }
 
 func registerEncryptionFlags(cmd *cobra.Command) {
@@ -125,7 +160,7 @@ func registerPipelineFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().IntVarP(&goCDPipelineInstance, "instance", "i", defaultInstanceCount,
 		"instance number of a pipeline")
 	cmd.PersistentFlags().StringVarP(&goCDPipelineETAG, "etag", "", "",
-		"etag used to identify the pipeline config. If you don't have one get it by using pipeline get command")
+		"etag of the pipeline config the changes are based on, the changes made to it since are merged while updating. If you don't have one get it by using pipeline get command")
 	cmd.PersistentFlags().StringVarP(&goCDPipelineMessage, "message", "m", "",
 		"message to be passed while pausing/unpausing or commenting on pipeline present in GoCD")
 	cmd.PersistentFlags().BoolVarP(&goCDPipelinePause, "pause", "", false,
@@ -175,6 +210,8 @@ func registerAgentsFlags(cmd *cobra.Command) {
 		"id of the agent on whom the action is to be performed")
 	cmd.PersistentFlags().StringVarP(&agentName, "name", "", "",
 		"name of the agent on whom the action is to be performed")
+
+	registerFlagCompletion(cmd, "name", completeResourceNames(resourceAgent))
 }
 
 func registerAgentsFilterFlags(cmd *cobra.Command) {
@@ -188,6 +225,8 @@ func registerAgentsFilterFlags(cmd *cobra.Command) {
 		"when enabled, it fetches only the disabled agents")
 	cmd.PersistentFlags().StringVarP(&agentName, "name", "", "",
 		"agent's name or pattern to match while filtering the results")
+
+	registerFlagCompletion(cmd, "name", completeResourceNames(resourceAgent))
 }
 
 func registerJobsNStageFlags(cmd *cobra.Command) {
@@ -201,6 +240,8 @@ func registerJobsNStageFlags(cmd *cobra.Command) {
 		"instance of the stage that should be considered")
 	cmd.PersistentFlags().StringSliceVarP(&stageConfig.Jobs, "job", "", nil,
 		"list of jobs that should be triggered")
+
+	registerFlagCompletion(cmd, "pipeline", completeResourceNames(resourcePipeline))
 }
 
 func registerDanglingFlags(cmd *cobra.Command) {
@@ -211,4 +252,6 @@ func registerDanglingFlags(cmd *cobra.Command) {
 func registerElasticProfilesFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringSliceVarP(&elasticProfiles, "elastic-profile", "", nil,
 		"elastic profile names to be operated on")
+
+	registerFlagCompletion(cmd, "elastic-profile", completeResourceNames(resourceElasticAgentProfile))
 }
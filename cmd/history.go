@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/history"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/thoas/go-funk"
+)
+
+const (
+	goCdHistoryDirName = "history"
+	// historyMaxEntries and historyMaxAge are the entries kept in the history, the ones beyond are pruned before saving new ones.
+	historyMaxEntries = 1000
+	historyMaxAge     = 90 * 24 * time.Hour
+)
+
+var (
+	historyPruneMaxEntries int
+	historyPruneMaxAge     time.Duration
+)
+
+// historySecretFlags are the flags whose values are not saved to the history, along with the command that was run.
+var historySecretFlags = []string{"password", "auth-token", "cipher-key"}
+
+// historyResources are the paths of the resources saved to the history, along with their kinds. The name of the resource is the one
+// captured by the path, the resources that GoCD server has only one of are named as in the manifests.
+// Only the kinds supported by 'apply' are saved, since the resources are rolled back through them.
+var historyResources = []struct {
+	path *regexp.Regexp
+	kind string
+	name string
+}{
+	{path: regexp.MustCompile(`/api/admin/config/server/site_urls$`), kind: manifest.KindSiteURL, name: siteURLName},
+	{path: regexp.MustCompile(`/api/config/mailserver$`), kind: manifest.KindMailServer, name: mailServerName},
+	{path: regexp.MustCompile(`/api/admin/config/server/default_job_timeout$`), kind: manifest.KindJobTimeout, name: jobTimeoutName},
+	{path: regexp.MustCompile(`/api/config/backup$`), kind: manifest.KindBackupConfig, name: backupConfigName},
+	{path: regexp.MustCompile(`/api/admin/plugin_settings/([^/]+)$`), kind: manifest.KindPluginSettings},
+	{path: regexp.MustCompile(`/api/admin/security/auth_configs/([^/]+)$`), kind: manifest.KindAuthConfig},
+	{path: regexp.MustCompile(`/api/admin/security/roles/([^/]+)$`), kind: manifest.KindRole},
+	{path: regexp.MustCompile(`/api/admin/elastic/cluster_profiles/([^/]+)$`), kind: manifest.KindClusterProfile},
+	{path: regexp.MustCompile(`/api/elastic/profiles/([^/]+)$`), kind: manifest.KindElasticAgentProfile},
+	{path: regexp.MustCompile(`/api/admin/artifact_stores/([^/]+)$`), kind: manifest.KindArtifactStore},
+	{path: regexp.MustCompile(`/api/admin/config_repos/([^/]+)$`), kind: manifest.KindConfigRepo},
+	{path: regexp.MustCompile(`/api/admin/pipeline_groups/([^/]+)$`), kind: manifest.KindPipelineGroup},
+	{path: regexp.MustCompile(`/api/admin/pipelines/([^/]+)$`), kind: manifest.KindPipeline},
+	{path: regexp.MustCompile(`/api/admin/environments/([^/]+)$`), kind: manifest.KindEnvironment},
+}
+
+func registerHistoryCommand() *cobra.Command {
+	registerHistoryCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Command to operate on the history of the resources modified by the cli",
+		Long: `Command to operate on the history of the resources modified by the cli, saved under $HOME/.gocd/history.
+Before every call that updates, patches or deletes a single resource of the kinds supported by 'apply', the resource is saved to the history
+along with the user, the time, the profile and the command that modified it. The resources saved could be restored using 'rollback'.
+Saving the resources to the history could be disabled with --no-history.
+The latest 1000 entries saved within 90 days are kept, the older ones are pruned before saving new ones or by 'history prune'.`,
+		Example: `gocd-cli history list -o table`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Usage()
+		},
+	}
+
+	registerHistoryCmd.SetUsageTemplate(getUsageTemplate())
+
+	registerHistoryCmd.AddCommand(getHistoryListCommand())
+	registerHistoryCmd.AddCommand(getHistoryPruneCommand())
+
+	for _, command := range registerHistoryCmd.Commands() {
+		command.SilenceUsage = true
+	}
+
+	return registerHistoryCmd
+}
+
+func getHistoryListCommand() *cobra.Command {
+	historyListCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "Command to LIST the resources saved to the history, the latest first",
+		Example: `gocd-cli history list -o table`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCLIClientWithoutCache,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			journal, err := getJournal()
+			if err != nil {
+				return err
+			}
+
+			entries, err := journal.List()
+			if err != nil {
+				return err
+			}
+
+			if cliRenderer.Table {
+				cliCfg.TableData = append(cliCfg.TableData, []string{"ID", "Time", "Profile", "User", "Method", "Kind", "Name", "Command"})
+				for _, entry := range entries {
+					cliCfg.TableData = append(cliCfg.TableData, []string{
+						entry.ID, entry.Time.Local().Format(time.RFC3339), entry.Profile, entry.User, entry.Method, entry.Kind, entry.Name, entry.Command,
+					})
+				}
+
+				return cliRenderer.Render(cliCfg.TableData)
+			}
+
+			for index := range entries {
+				entries[index].Object = nil
+			}
+
+			return cliRenderer.Render(entries)
+		},
+	}
+
+	return historyListCmd
+}
+
+func getHistoryPruneCommand() *cobra.Command {
+	historyPruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Command to PRUNE the entries of the history beyond the latest ones, along with the ones older than the age set",
+		Example: `gocd-cli history prune
+gocd-cli history prune --max-entries 100 --max-age 168h`,
+		Args:    cobra.NoArgs,
+		PreRunE: setCLIClientWithoutCache,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			journal, err := getJournal()
+			if err != nil {
+				return err
+			}
+
+			removed, err := journal.Prune(historyPruneMaxEntries, historyPruneMaxAge)
+			if err != nil {
+				return err
+			}
+
+			return cliRenderer.Render(fmt.Sprintf("%d history entries were pruned from '%s'", len(removed), journal.Dir()))
+		},
+	}
+
+	historyPruneCmd.PersistentFlags().IntVarP(&historyPruneMaxEntries, "max-entries", "", historyMaxEntries,
+		"number of the latest entries to be kept, set it to 0 to not prune by the number of entries")
+	historyPruneCmd.PersistentFlags().DurationVarP(&historyPruneMaxAge, "max-age", "", historyMaxAge,
+		"age of the entries to be kept, ex: 168h, set it to 0 to not prune by the age of the entries")
+
+	return historyPruneCmd
+}
+
+func registerRollbackCommand() *cobra.Command {
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback <entry>",
+		Short: "Command to ROLLBACK a resource on GoCD server to the state saved in the history",
+		Long: `Command to ROLLBACK a resource on GoCD server to the state saved in the history, the entries could be listed using 'history list'.
+The resource is updated back to the state saved, or created again when it was deleted since, the same way 'apply' would do it.
+The changes are shown as a diff before confirmation.
+A prefix of the entry is accepted as long as it identifies a single entry. Only the entries saved from the GoCD server of the profile in use could be rolled back.`,
+		Example: `gocd-cli history list -o table
+gocd-cli rollback 20261017T101530-3f9a2c
+gocd-cli rollback 20261017T1015 --dry-run`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           setCLIClient,
+		ValidArgsFunction: completeHistoryEntries,
+		RunE: func(_ *cobra.Command, args []string) error {
+			journal, err := getJournal()
+			if err != nil {
+				return err
+			}
+
+			entry, err := journal.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			return rollbackEntry(entry)
+		},
+	}
+
+	rollbackCmd.SetUsageTemplate(getUsageTemplate())
+	rollbackCmd.SilenceUsage = true
+
+	return rollbackCmd
+}
+
+// rollbackEntry restores the resource saved by the entry, as if it was declared in a manifest passed to 'apply'.
+// The resource is updated when it exists on GoCD server, and created otherwise.
+func rollbackEntry(entry *history.Entry) error {
+	if !isSameServer(entry.Server, cliCfg.URL) {
+		return &errors.ValidationError{Message: fmt.Sprintf("history entry '%s' was saved from GoCD server '%s', but the profile in use points to '%s'",
+			entry.ID, entry.Server, cliCfg.URL)}
+	}
+
+	planner, ok := manifestPlanners[entry.Kind]
+	if !ok {
+		return &errors.ValidationError{Message: fmt.Sprintf("history entry '%s' does not hold a resource of the kinds supported, it cannot be rolled back", entry.ID)}
+	}
+
+	var object interface{}
+	if err := json.Unmarshal(entry.Object, &object); err != nil {
+		return err
+	}
+
+	document, err := manifest.NewDocument(entry.Kind, fmt.Sprintf("history entry '%s'", entry.ID), object, manifestOmittedFields[entry.Kind]...)
+	if err != nil {
+		return err
+	}
+
+	change, err := planner(document)
+	if err != nil {
+		return err
+	}
+
+	diffIdentified, err := change.diff()
+	if err != nil {
+		return err
+	}
+
+	if len(diffIdentified) == 0 {
+		return &errors.NoChangesError{Message: fmt.Sprintf("%s '%s' is already in the state saved by history entry '%s', nothing to roll back", entry.Kind, change.name, entry.ID)}
+	}
+
+	action := "updated"
+	if change.existing == nil {
+		action = "created"
+	}
+
+	fmt.Printf("# %s '%s' would be %s to the state saved at '%s' before '%s'\n%s\n",
+		entry.Kind, change.name, action, entry.Time.Local().Format(time.RFC3339), entry.Command, diffIdentified)
+
+	cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to roll back %s '%s' [y/n]", strings.ToLower(entry.Kind), change.name)
+
+	if err = cliCfg.confirm(); err != nil {
+		return err
+	}
+
+	if err = change.apply(); err != nil {
+		return err
+	}
+
+	return cliRenderer.Render(fmt.Sprintf("%s '%s' rolled back to history entry '%s'", entry.Kind, change.name, entry.ID))
+}
+
+// resolveHistoryResource returns the kind and the name of the resource the call is made to, when it is one of historyResources.
+func resolveHistoryResource(req *http.Request) (string, string, bool) {
+	for _, resource := range historyResources {
+		matched := resource.path.FindStringSubmatch(req.URL.EscapedPath())
+		if matched == nil {
+			continue
+		}
+
+		if len(matched) == 1 {
+			return resource.kind, resource.name, true
+		}
+
+		name, err := url.PathUnescape(matched[1])
+		if err != nil {
+			return "", "", false
+		}
+
+		return resource.kind, name, true
+	}
+
+	return "", "", false
+}
+
+// getHistoryEntry returns the details saved to the history along with every resource, it is called every time a resource is saved.
+func (cfg *Config) getHistoryEntry() history.Entry {
+	return history.Entry{
+		User:     getOSUser(),
+		GoCDUser: cfg.Auth.UserName,
+		Profile:  cfg.getProfileName(),
+		Server:   cfg.URL,
+		Command:  getCommandLine(),
+	}
+}
+
+// getCommandLine returns the command being run along with the flags set, the values of the flags holding secrets are redacted.
+func getCommandLine() string {
+	if runningCommand == nil {
+		return ""
+	}
+
+	parts := append([]string{runningCommand.CommandPath()}, runningArgs...)
+
+	runningCommand.Flags().Visit(func(flag *pflag.Flag) {
+		value := flag.Value.String()
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			value = strings.Join(sliceValue.GetSlice(), ",")
+		}
+
+		if funk.Contains(historySecretFlags, flag.Name) {
+			value = "<redacted>"
+		}
+
+		parts = append(parts, fmt.Sprintf("--%s=%s", flag.Name, value))
+	})
+
+	return strings.Join(parts, " ")
+}
+
+func getOSUser() string {
+	if currentUser, err := user.Current(); err == nil {
+		return currentUser.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+func getJournal() (*history.Journal, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return history.New(filepath.Join(home, goCdCacheDirName, goCdHistoryDirName)), nil
+}
+
+func completeHistoryEntries(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	journal, err := getJournal()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	entries, err := journal.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.ID, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s %s %s", entry.ID, entry.Method, entry.Kind, entry.Name))
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/structdiff"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/spf13/cobra"
+)
+
+// maxUpdateAttempts is the number of times an update is attempted, when it keeps conflicting with the changes made on GoCD server.
+const maxUpdateAttempts = 3
+
+// updateMergeHelp is the help of the update commands, on how the changes made on GoCD server since the update was based on are merged.
+const updateMergeHelp = `The changes made on GoCD server since the version the update is based on, either the one set in the input or the one fetched
+while updating, are merged only when that version was saved by --save-versions (or GOCD_SAVE_VERSIONS=true).
+The update fails as conflicting otherwise, and should be made again on top of the resource as it is on GoCD server.`
+
+// rebaseResource returns the resource desired, carrying the etag of the resource fetched from GoCD server.
+// When the resource desired is based on an older version of it, identified by the etag it carries, the changes made on GoCD server
+// since that version are merged into it. The changes made to the same fields are not merged, but are reported as conflicts.
+func rebaseResource[T any](fetched, desired T) (T, error) {
+	eTag := getETag(desired)
+	if len(eTag) == 0 || transport.VersionKey(eTag) == transport.VersionKey(getETag(fetched)) {
+		setETag(&desired, getETag(fetched))
+
+		return desired, nil
+	}
+
+	base, err := getResourceVersion[T](eTag)
+	if err != nil {
+		return desired, err
+	}
+
+	cliLogger.Infof("resource was modified on GoCD server since the version '%s' the input is based on, merging the changes made since", eTag)
+
+	return mergeResource(base, fetched, desired)
+}
+
+// updateResource updates the resource desired, that is based on the resource fetched. When the update is rejected since the resource
+// was modified on GoCD server in the meantime, it is fetched again and the update is retried with the changes made since merged,
+// once the merged changes are confirmed the same way as the ones before. The changes are merged only against the version saved by
+// --save-versions, the update fails as conflicting otherwise. A plan being applied is not retried, as it no longer matches.
+func updateResource[T, R any](fetched, desired T, fetch func() (T, error), update func(T) (R, error)) (R, error) {
+	basedOn := fetched
+
+	for attempt := 1; ; attempt++ {
+		response, err := update(desired)
+		// a plan is applied only to the state it was made against, it is not merged with the changes made since.
+		if err == nil || errors.StatusCode(err) != http.StatusPreconditionFailed || attempt == maxUpdateAttempts || goCdPlan != nil {
+			return response, err
+		}
+
+		base, versionErr := getResourceVersion[T](getETag(basedOn))
+		if versionErr != nil {
+			return response, &errors.ConflictError{
+				Message: "resource was modified on GoCD server while it was being updated, and the version the update is based on was not saved " +
+					"to merge the changes made since, update again or pass --save-versions to merge them",
+			}
+		}
+
+		cliLogger.Infof("resource was modified on GoCD server while it was being updated, merging the changes made since")
+
+		theirs, err := fetch()
+		if err != nil {
+			return response, err
+		}
+
+		if desired, err = mergeResource(base, theirs, desired); err != nil {
+			return response, err
+		}
+
+		mergedData, err := getPlanState(desired)
+		if err != nil {
+			return response, err
+		}
+
+		if err = cliCfg.CheckDiffAndAllow(theirs, desired, mergedData); err != nil {
+			return response, err
+		}
+
+		basedOn = theirs
+	}
+}
+
+// setUpdateMergeHelp sets the help of the update command, on how the changes made on GoCD server are merged into the update.
+func setUpdateMergeHelp(cmd *cobra.Command) {
+	cmd.Long = fmt.Sprintf("%s.\n%s", strings.TrimSuffix(cmd.Short, "."), updateMergeHelp)
+}
+
+// mergeResource merges the changes made to base in theirs and ours, the merged resource carries the etag of theirs.
+func mergeResource[T any](base, theirs, ours T) (T, error) {
+	var merged T
+
+	eTag := getETag(theirs)
+	for _, object := range []*T{&base, &theirs, &ours} {
+		setETag(object, "")
+	}
+
+	mergedValue, conflicts, err := structdiff.Merge(base, theirs, ours)
+	if err != nil {
+		return merged, err
+	}
+
+	if len(conflicts) != 0 {
+		return merged, &errors.ConflictError{
+			Message: fmt.Sprintf("changes to the resource conflict with the ones made on GoCD server since the version they are based on, "+
+				"resolve them and update again:\n%s", structdiff.ConflictsString(conflicts)),
+		}
+	}
+
+	mergedJSON, err := json.Marshal(mergedValue)
+	if err != nil {
+		return merged, err
+	}
+
+	if err = json.Unmarshal(mergedJSON, &merged); err != nil {
+		return merged, err
+	}
+
+	if changes, err := structdiff.Diff(ours, merged); err == nil && len(changes) != 0 {
+		cliLogger.Infof("changes made on GoCD server merged into the resource:\n%s", structdiff.String(changes))
+	}
+
+	setETag(&merged, eTag)
+
+	return merged, nil
+}
+
+// getResourceVersion returns the version of the resource fetched earlier with the etag passed, from the versions cached.
+func getResourceVersion[T any](eTag string) (T, error) {
+	var version T
+
+	notFound := &errors.ConflictError{
+		Message: fmt.Sprintf("resource was modified on GoCD server since the version '%s' the input is based on, and the version is not known "+
+			"to merge the changes made since, fetch the resource again with --save-versions and reapply the changes", eTag),
+	}
+
+	versions, err := getVersionCache()
+	if err != nil {
+		return version, notFound
+	}
+
+	var versionJSON json.RawMessage
+	if !versions.Get(transport.VersionKey(eTag), &versionJSON) {
+		return version, notFound
+	}
+
+	if err = json.Unmarshal(versionJSON, &version); err != nil {
+		return version, err
+	}
+
+	setETag(&version, eTag)
+
+	return version, nil
+}
+
+// setETag sets the etag of the resource passed by its pointer, resources without one are left as they are.
+func setETag(object interface{}, eTag string) {
+	value := reflect.ValueOf(object)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	if field := value.Elem().FieldByName("ETAG"); field.IsValid() && field.Kind() == reflect.String && field.CanSet() {
+		field.SetString(eTag)
+	}
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFromProfile   string
+	migrateToProfile     string
+	migrateKinds         []string
+	migrateNames         []string
+	migrateSource        gocd.GoCd
+	migrateSourceGateway *transport.Gateway
+)
+
+func registerMigrateCommand() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Command to MIGRATE the resources from one GoCD server to another, re-encrypting the secure values",
+		Long: fmt.Sprintf(`Command to MIGRATE the resources from one GoCD server to another, re-encrypting the secure values.
+The resources managed through the GoCD server of --from-profile are created or updated on the one of --to-profile,
+the changes to every resource are shown as a diff before a single confirmation, same as 'apply'.
+The resources defined in config repos are not migrated. Kinds supported are: %s.
+
+The encrypted values are decrypted with the cipher key of the source server passed via --cipher-key or --cipher-key-path,
+and encrypted again by the target server, since the servers do not share the cipher key.
+As every encryption yields a different value, the secure values are always shown as changed in the diff.`, strings.Join(manifest.Kinds(), ", ")),
+		Example: `gocd-cli migrate --from-profile old --to-profile new --cipher-key-path old/cipher.aes
+gocd-cli migrate --from-profile old --to-profile new --kind Pipeline --kind PipelineGroup --name 'payments-*' --cipher-key-path old/cipher.aes`,
+		Args:    cobra.NoArgs,
+		PreRunE: setMigrateClients,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			defer closeMigrateSource()
+
+			documents, err := getMigrateDocuments()
+			if err != nil {
+				return err
+			}
+
+			if len(documents) == 0 {
+				return &errors.NoChangesError{Message: fmt.Sprintf("no resources selected from profile '%s', nothing to migrate, quitting", migrateFromProfile)}
+			}
+
+			return applyManifestDocuments(documents)
+		},
+	}
+
+	registerManifestKindFlag(migrateCmd, &migrateKinds, "kinds of the resources to be migrated, defaults to all the kinds supported")
+	registerEncryptionFlags(migrateCmd)
+
+	migrateCmd.PersistentFlags().StringVarP(&migrateFromProfile, "from-profile", "", "",
+		"name of the context to read the resources from")
+	migrateCmd.PersistentFlags().StringVarP(&migrateToProfile, "to-profile", "", "",
+		"name of the context to create or update the resources on")
+	migrateCmd.PersistentFlags().StringSliceVarP(&migrateNames, "name", "", nil,
+		"names of the resources to be migrated, supports glob patterns (ex: 'payments-*'), defaults to all the resources")
+
+	for _, flagName := range []string{"from-profile", "to-profile"} {
+		if err := migrateCmd.MarkPersistentFlagRequired(flagName); err != nil {
+			cliLogger.Fatalf("%v", err)
+		}
+
+		registerFlagCompletion(migrateCmd, flagName, completeContextNames)
+	}
+
+	migrateCmd.SetUsageTemplate(getUsageTemplate())
+	migrateCmd.SilenceUsage = true
+
+	return migrateCmd
+}
+
+// setMigrateClients sets up the client of --from-profile as the source, and the one of --to-profile as the client used by the command.
+func setMigrateClients(cmd *cobra.Command, args []string) error {
+	if migrateFromProfile == migrateToProfile {
+		return &errors.ValidationError{Message: "--from-profile and --to-profile should not be the same"}
+	}
+
+	var err error
+
+	if migrateSource, migrateSourceGateway, err = getProfileClient(cmd, args, migrateFromProfile); err != nil {
+		return err
+	}
+
+	if err = cmd.Root().PersistentFlags().Set("profile", migrateToProfile); err != nil {
+		closeMigrateSource()
+
+		return err
+	}
+
+	if err = setCLIClient(cmd, args); err != nil {
+		closeMigrateSource()
+
+		return err
+	}
+
+	return nil
+}
+
+// getMigrateDocuments fetches the resources selected from the source server, as documents that could be applied on the target server.
+func getMigrateDocuments() ([]manifest.Document, error) {
+	target := client
+	client = migrateSource
+
+	objects, err := fetchManifestObjects(migrateKinds)
+
+	client = target
+
+	if err != nil {
+		return nil, err
+	}
+
+	sourceCipherKey, err := getSourceCipherKey()
+	if err != nil {
+		return nil, err
+	}
+
+	reEncrypted := make(map[string]string)
+	documents := make([]manifest.Document, 0, len(objects))
+
+	for _, object := range objects {
+		selected, err := isNameSelected(migrateNames, object.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !selected {
+			continue
+		}
+
+		document, err := manifest.NewDocument(object.kind, fmt.Sprintf("profile '%s'", migrateFromProfile), object.object, manifestOmittedFields[object.kind]...)
+		if err != nil {
+			return nil, err
+		}
+
+		if cliCfg.DryRun {
+			cliLogger.Debugf("--dry-run is opted, encrypted values of %s '%s' are shown as they are on the source server", object.kind, object.name)
+		} else {
+			err = document.ReplaceSecrets(func(value string) (string, error) {
+				return reEncrypt(value, sourceCipherKey, reEncrypted)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+// reEncrypt decrypts the value with the cipher key of the source server and encrypts it with the target server.
+// The values re-encrypted are remembered, so that a secret shared by many resources is encrypted only once.
+func reEncrypt(value, sourceCipherKey string, reEncrypted map[string]string) (string, error) {
+	if encryptedValue, ok := reEncrypted[value]; ok {
+		return encryptedValue, nil
+	}
+
+	if len(sourceCipherKey) == 0 {
+		return "", &errors.ValidationError{Message: fmt.Sprintf("resources hold encrypted values, cipher key of the server of profile '%s' "+
+			"should be passed via --cipher-key or --cipher-key-path to re-encrypt them", migrateFromProfile)}
+	}
+
+	plainText, err := migrateSource.DecryptText(value, sourceCipherKey)
+	if err != nil {
+		return "", err
+	}
+
+	encrypted, err := client.EncryptText(plainText)
+	if err != nil {
+		return "", err
+	}
+
+	reEncrypted[value] = encrypted.EncryptedValue
+
+	return encrypted.EncryptedValue, nil
+}
+
+func getSourceCipherKey() (string, error) {
+	if len(cipherKeyPath) == 0 {
+		return cipherKey, nil
+	}
+
+	out, err := os.ReadFile(cipherKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// isNameSelected returns true if the name of the resource matches any of the names passed via --name, or when none is passed.
+func isNameSelected(names []string, name string) (bool, error) {
+	if len(names) == 0 {
+		return true, nil
+	}
+
+	for _, pattern := range names {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, &errors.ValidationError{Message: fmt.Sprintf("invalid name selector '%s': %v", pattern, err)}
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func closeMigrateSource() {
+	if migrateSourceGateway == nil {
+		return
+	}
+
+	if err := migrateSourceGateway.Close(); err != nil {
+		cliLogger.Debugf("closing loopback gateway of the source server errored with '%v'", err)
+	}
+
+	migrateSourceGateway = nil
+}
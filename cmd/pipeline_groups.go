@@ -1,20 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
 	"github.com/thoas/go-funk"
-	"gopkg.in/yaml.v3"
 )
 
 func registerPipelineGroupsCommand() *cobra.Command {
@@ -100,10 +95,11 @@ func getPipelineGroupsCommand() *cobra.Command {
 
 func getPipelineGroupCommand() *cobra.Command {
 	getPipelineGroupCmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET a specific pipeline group present in GoCD [https://api.gocd.org/current/#get-a-pipeline-group]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get",
+		Short:             "Command to GET a specific pipeline group present in GoCD [https://api.gocd.org/current/#get-a-pipeline-group]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipelineGroup),
+		PreRunE:           setCLIClient,
 		Example: `gocd-cli pipeline-group get movies --query "pipelines.[*] | name" -o yaml
 // should return only the list of pipeline names based on the query`,
 		RunE: func(_ *cobra.Command, args []string) error {
@@ -173,17 +169,8 @@ func updatePipelineGroupCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &ppGroup); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &ppGroup); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &ppGroup); err != nil {
+				return err
 			}
 
 			pipelineGroupFetched, err := client.GetPipelineGroup(ppGroup.Name)
@@ -197,22 +184,19 @@ func updatePipelineGroupCommand() *cobra.Command {
 				}
 			}
 
-			if len(ppGroup.ETAG) == 0 {
-				ppGroup.ETAG = pipelineGroupFetched.ETAG
+			if ppGroup, err = rebaseResource(pipelineGroupFetched, ppGroup); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "pipeline-group", ppGroup.Name)
 
-			existing, err := diffCfg.String(pipelineGroupFetched)
-			if err != nil {
+			if err = cliCfg.CheckDiffAndAllow(pipelineGroupFetched, ppGroup, object.String()); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
-				return err
-			}
-
-			env, err := client.UpdatePipelineGroup(ppGroup)
+			env, err := updateResource(pipelineGroupFetched, ppGroup, func() (gocd.PipelineGroup, error) {
+				return client.GetPipelineGroup(ppGroup.Name)
+			}, client.UpdatePipelineGroup)
 			if err != nil {
 				return err
 			}
@@ -228,31 +212,25 @@ func updatePipelineGroupCommand() *cobra.Command {
 	updatePipelineGroupCmd.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a pipeline group by this name doesn't already exist, run create")
 
+	setUpdateMergeHelp(updatePipelineGroupCmd)
+
 	return updatePipelineGroupCmd
 }
 
 func deletePipelineGroupCommand() *cobra.Command {
 	deletePipelineGroupCmd := &cobra.Command{
-		Use:     "delete",
-		Short:   "Command to DELETE the specified pipeline group from GoCD [https://api.gocd.org/current/#delete-a-pipeline-group]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline-group delete movies`,
+		Use:               "delete",
+		Short:             "Command to DELETE the specified pipeline group from GoCD [https://api.gocd.org/current/#delete-a-pipeline-group]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipelineGroup),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline-group delete movies`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			pipelineGroupName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete pipeline-group '%s' [y/n]", pipelineGroupName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeletePipelineGroup(pipelineGroupName); err != nil {
@@ -320,17 +298,8 @@ func createPipelineGroup(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &ppGroup); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &ppGroup); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &ppGroup); err != nil {
+		return err
 	}
 
 	if err = client.CreatePipelineGroup(ppGroup); err != nil {
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/fatih/color"
-	"github.com/ghodss/yaml"
 	goYAML "github.com/goccy/go-yaml"
 	"github.com/nikhilsbhat/common/content"
 	clierrors "github.com/nikhilsbhat/gocd-cli/pkg/errors"
@@ -272,11 +271,12 @@ func getPipelineVSMCommand() *cobra.Command {
 
 func getPipelineCommand() *cobra.Command {
 	getPipelineCmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET pipeline config of a specified pipeline present in GoCD [https://api.gocd.org/current/#get-pipeline-config]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline get sample-pipeline --query "[*] | name eq sample-group"`,
+		Use:               "get",
+		Short:             "Command to GET pipeline config of a specified pipeline present in GoCD [https://api.gocd.org/current/#get-pipeline-config]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline get sample-pipeline --query "[*] | name eq sample-group"`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetPipelineConfig(args[0])
@@ -284,6 +284,8 @@ func getPipelineCommand() *cobra.Command {
 					return err
 				}
 
+				cliLogger.Debugf("etag of pipeline config '%s' is '%s', pass it as --etag while updating it", args[0], response.ETAG)
+
 				if len(jsonQuery) != 0 {
 					cliLogger.Debugf(queryEnabledMessage, jsonQuery)
 
@@ -317,11 +319,12 @@ func getPipelineCommand() *cobra.Command {
 
 func getPipelineScheduleCommand() *cobra.Command {
 	getPipelineScheduleCmd := &cobra.Command{
-		Use:     "last-schedule",
-		Short:   "Command to GET last scheduled time of the pipeline present in GoCD [/pipelineHistory.json?pipelineName=nameOfThePipeline]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline last-schedule sample-pipeline`,
+		Use:               "last-schedule",
+		Short:             "Command to GET last scheduled time of the pipeline present in GoCD [/pipelineHistory.json?pipelineName=nameOfThePipeline]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline last-schedule sample-pipeline`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			response, err := client.GetPipelineSchedules(args[0], "0", "1")
 			if err != nil {
@@ -367,9 +370,10 @@ func getPipelineHistoryCommand() *cobra.Command {
 		Long: `Command leverages GoCD api [https://api.gocd.org/current/#get-pipeline-history] to get the history
 This would be an expensive operation especially when you have more pipeline instance to fetch
 Prefer invoking this command when GoCD is not serving huge traffic`,
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline history sample-pipeline`,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline history sample-pipeline`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			response, err := client.GetPipelineRunHistory(args[0], "10", delay)
 			if err != nil {
@@ -528,17 +532,8 @@ func createPipelineCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &pipelineConfig); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &pipelineConfig); err != nil {
-					return err
-				}
-			default:
-				return &clierrors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &pipelineConfig); err != nil {
+				return err
 			}
 
 			if goCDPausePipelineAtStart {
@@ -569,7 +564,9 @@ func updatePipelineCommand() *cobra.Command {
 		Args:    cobra.NoArgs,
 		PreRunE: setCLIClient,
 		Example: `gocd-cli pipeline update --from-file sample-movies.yaml --log-level debug
-// the inputs can be passed either from file using '--from-file' flag or entire content as argument to command`,
+gocd-cli pipeline update --from-file sample-movies.yaml --etag 61406622382e51c2079c11dcbdb978fb
+// the inputs can be passed either from file using '--from-file' flag or entire content as argument to command
+// when --etag of the version the changes are based on is passed, the changes made to the pipeline since are merged`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			var pipelineConfig gocd.PipelineConfig
 
@@ -578,17 +575,8 @@ func updatePipelineCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &pipelineConfig); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &pipelineConfig); err != nil {
-					return err
-				}
-			default:
-				return &clierrors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &pipelineConfig); err != nil {
+				return err
 			}
 
 			pipelineConfigFetched, err := client.GetPipelineConfig(pipelineConfig.Name)
@@ -596,18 +584,22 @@ func updatePipelineCommand() *cobra.Command {
 				return err
 			}
 
-			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "pipeline-config", pipelineConfig.Name)
+			// etag of the pipeline config is not a part of its definition, hence it is passed by --etag.
+			pipelineConfig.ETAG = goCDPipelineETAG
 
-			existing, err := diffCfg.String(pipelineConfigFetched)
-			if err != nil {
+			if pipelineConfig, err = rebaseResource(pipelineConfigFetched, pipelineConfig); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
+			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "pipeline-config", pipelineConfig.Name)
+
+			if err = cliCfg.CheckDiffAndAllow(pipelineConfigFetched, pipelineConfig, object.String()); err != nil {
 				return err
 			}
 
-			response, err := client.UpdatePipelineConfig(pipelineConfig)
+			response, err := updateResource(pipelineConfigFetched, pipelineConfig, func() (gocd.PipelineConfig, error) {
+				return client.GetPipelineConfig(pipelineConfig.Name)
+			}, client.UpdatePipelineConfig)
 			if err != nil {
 				return err
 			}
@@ -622,31 +614,25 @@ func updatePipelineCommand() *cobra.Command {
 
 	registerPipelineFlags(updatePipelineGroupCmd)
 
+	setUpdateMergeHelp(updatePipelineGroupCmd)
+
 	return updatePipelineGroupCmd
 }
 
 func deletePipelineCommand() *cobra.Command {
 	deletePipelineCmd := &cobra.Command{
-		Use:     "delete",
-		Short:   "Command to DELETE the specified pipeline from GoCD [https://api.gocd.org/current/#delete-a-pipeline]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline delete movies`,
+		Use:               "delete",
+		Short:             "Command to DELETE the specified pipeline from GoCD [https://api.gocd.org/current/#delete-a-pipeline]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline delete movies`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			pipelineName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete pipeline '%s' [y/n]", pipelineName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeletePipeline(pipelineName); err != nil {
@@ -662,11 +648,12 @@ func deletePipelineCommand() *cobra.Command {
 
 func getPipelineStateCommand() *cobra.Command {
 	getPipelineStateCmd := &cobra.Command{
-		Use:     "status",
-		Short:   "Command to GET status of a specific pipeline present in GoCD [https://api.gocd.org/current/#get-pipeline-status]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline status sample-pipeline`,
+		Use:               "status",
+		Short:             "Command to GET status of a specific pipeline present in GoCD [https://api.gocd.org/current/#get-pipeline-status]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline status sample-pipeline`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetPipelineState(args[0])
@@ -707,11 +694,12 @@ func getPipelineStateCommand() *cobra.Command {
 
 func getPipelineInstanceCommand() *cobra.Command {
 	getPipelineInstanceCmd := &cobra.Command{
-		Use:     "instance",
-		Short:   "Command to GET instance of a specific pipeline present in GoCD [https://api.gocd.org/current/#get-pipeline-instance]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline instance sample-pipeline --instance 10`,
+		Use:               "instance",
+		Short:             "Command to GET instance of a specific pipeline present in GoCD [https://api.gocd.org/current/#get-pipeline-instance]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline instance sample-pipeline --instance 10`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			pipelineObject := gocd.PipelineObject{
 				Name:    args[0],
@@ -750,9 +738,10 @@ func pauseUnpausePipelineCommand() *cobra.Command {
 		Use: "action",
 		Short: `Command to PAUSE/UNPAUSE a specific pipeline present in GoCD,
               [https://api.gocd.org/current/#pause-a-pipeline,https://api.gocd.org/current/#unpause-a-pipeline]`,
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline action sample-pipeline --pause/--un-pause`,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline action sample-pipeline --pause/--un-pause`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			var action string
 			if goCDPipelinePause {
@@ -779,11 +768,12 @@ func pauseUnpausePipelineCommand() *cobra.Command {
 
 func schedulePipelineCommand() *cobra.Command {
 	schedulePipelineCmd := &cobra.Command{
-		Use:     "schedule",
-		Short:   "Command to SCHEDULE a specific pipeline present in GoCD [https://api.gocd.org/current/#scheduling-pipelines]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline schedule sample --from-file schedule-config.yaml`,
+		Use:               "schedule",
+		Short:             "Command to SCHEDULE a specific pipeline present in GoCD [https://api.gocd.org/current/#scheduling-pipelines]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline schedule sample --from-file schedule-config.yaml`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var schedule gocd.Schedule
 			object, err := readObject(cmd)
@@ -791,17 +781,8 @@ func schedulePipelineCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &schedule); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &schedule); err != nil {
-					return err
-				}
-			default:
-				return &clierrors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &schedule); err != nil {
+				return err
 			}
 
 			if err = client.SchedulePipeline(args[0], schedule); err != nil {
@@ -819,11 +800,12 @@ func schedulePipelineCommand() *cobra.Command {
 
 func commentPipelineCommand() *cobra.Command {
 	commentOnPipelineCmd := &cobra.Command{
-		Use:     "comment",
-		Short:   "Command to COMMENT on a specific pipeline instance present in GoCD [https://api.gocd.org/current/#comment-on-pipeline-instance]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline comment --message "message to be commented"`,
+		Use:               "comment",
+		Short:             "Command to COMMENT on a specific pipeline instance present in GoCD [https://api.gocd.org/current/#comment-on-pipeline-instance]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline comment --message "message to be commented"`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			pipelineObject := gocd.PipelineObject{
 				Name:    args[0],
@@ -878,7 +860,7 @@ func listPipelinesCommand() *cobra.Command {
 				var goCdPipelines []string
 
 				if len(goCDEnvironments) != 0 && len(goCDPipelineGroups) != 0 {
-					cliLogger.Fatalf("pipelines cannot be filtered by 'environment' and 'pipeline-group' simultaneously")
+					return &clierrors.ValidationError{Message: "pipelines cannot be filtered by 'environment' and 'pipeline-group' simultaneously"}
 				}
 
 				if len(goCDEnvironments) != 0 {
@@ -981,8 +963,7 @@ func validatePipelinesCommand() *cobra.Command {
 			}
 
 			if !success {
-				cliLogger.Error("oops...!! pipeline syntax validation failed")
-				os.Exit(1)
+				return &clierrors.ValidationError{Message: "oops...!! pipeline syntax validation failed"}
 			}
 
 			fmt.Println("SUCCESS")
@@ -1014,9 +995,10 @@ func exportPipelineToConfigRepoFormatCommand() *cobra.Command {
 		Use: "export-format",
 		Short: "Command to export specified pipeline present in GoCD to appropriate config repo format " +
 			"[https://api.gocd.org/current/#export-pipeline-config-to-config-repo-format]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
-		Example: `gocd-cli pipeline change-config-repo-format pipeline1 --plugin-id yaml.config.plugin`,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourcePipeline),
+		PreRunE:           setCLIClient,
+		Example:           `gocd-cli pipeline change-config-repo-format pipeline1 --plugin-id yaml.config.plugin`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			response, err := client.ExportPipelineToConfigRepoFormat(args[0], goCdPluginObj.getPluginID())
 			if err != nil {
@@ -1186,8 +1168,8 @@ func findPipelineFilesCommand() *cobra.Command {
 
 			pipelineFiles, err := client.GetPipelineFiles(goCDPipelinesPath, nil, goCDPipelinesPatterns...)
 			if err != nil {
-				cliLogger.Fatalf("finding gocd pipelines under '%s', with patterns '%s' errored with: '%s'",
-					goCDPipelinesPath, strings.Join(goCDPipelinesPatterns, ","), err)
+				return &clierrors.CLIError{Message: fmt.Sprintf("finding gocd pipelines under '%s', with patterns '%s' errored with: '%s'",
+					goCDPipelinesPath, strings.Join(goCDPipelinesPatterns, ","), err)}
 			}
 
 			if detailed {
@@ -1238,8 +1220,8 @@ func showPipelineCommand() *cobra.Command {
 
 			pipelineFiles, err := client.GetPipelineFiles(goCDPipelinesPath, goCDPipelines, goCDPipelinesPatterns...)
 			if err != nil {
-				cliLogger.Fatalf("finding gocd pipelines under '%s', with patterns '%s' errored with: '%s'",
-					goCDPipelinesPath, strings.Join(goCDPipelinesPatterns, ","), err)
+				return &clierrors.CLIError{Message: fmt.Sprintf("finding gocd pipelines under '%s', with patterns '%s' errored with: '%s'",
+					goCDPipelinesPath, strings.Join(goCDPipelinesPatterns, ","), err)}
 			}
 
 			pipelinePathPatterns := filterIgnoredPipelines(pipelineFiles, ignore)
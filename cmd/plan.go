@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/plan"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// errPlanSaved stops the command once the plan is saved, it is not reported as a failure.
+	errPlanSaved = goerrors.New("plan saved")
+	// goCdPlan is the plan being applied by apply-plan, it is nil otherwise.
+	goCdPlan *plan.Plan
+	// runningCommand and runningArgs are the command being run and its args, recorded in the plans saved by --plan-out.
+	runningCommand *cobra.Command
+	runningArgs    []string
+)
+
+func registerApplyPlanCommand() *cobra.Command {
+	applyPlanCmd := &cobra.Command{
+		Use:   "apply-plan",
+		Short: "Command to APPLY the plan saved by an update command using --plan-out",
+		Long: `Command to APPLY the plan saved by an update command using --plan-out.
+The plan holds the state of the resource fetched from GoCD server while planning, the resource is fetched again
+and the plan is applied only if it was not modified since. Plans are applied without confirmation, since they are reviewed beforehand.`,
+		Example: `gocd-cli environment update --from-file environment.yaml --plan-out plan.json
+gocd-cli apply-plan plan.json`,
+		Args:    cobra.RangeArgs(1, 1),
+		PreRunE: setCLIClient,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if goCdPlan != nil {
+				return &errors.CLIError{Message: "plan cannot be applied from within a plan"}
+			}
+
+			planned, err := plan.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			if !isSameServer(planned.Server, cliCfg.URL) {
+				return &errors.ValidationError{Message: fmt.Sprintf("plan '%s' was made against GoCD server '%s', but the server set is '%s'",
+					args[0], planned.Server, cliCfg.URL)}
+			}
+
+			desiredFile, err := os.CreateTemp("", "gocd-cli-plan-*")
+			if err != nil {
+				return err
+			}
+
+			defer os.Remove(desiredFile.Name())
+
+			if _, err = desiredFile.WriteString(planned.Desired); err != nil {
+				return err
+			}
+
+			if err = desiredFile.Close(); err != nil {
+				return err
+			}
+
+			goCdPlan = planned
+			defer func() {
+				goCdPlan = nil
+			}()
+
+			cliLogger.Debugf("applying plan '%s' by running '%s'", args[0], strings.Join(planned.Command, " "))
+
+			if err = runPlannedCommand(cmd.Root(), planned, desiredFile.Name()); err != nil {
+				return err
+			}
+
+			if !planned.Verified() {
+				return &errors.ValidationError{Message: fmt.Sprintf("command '%s' of plan '%s' finished without checking the state of the resource, "+
+					"it does not support plans", strings.Join(planned.Command, " "), args[0])}
+			}
+
+			return nil
+		},
+	}
+
+	applyPlanCmd.SetUsageTemplate(getUsageTemplate())
+	applyPlanCmd.SilenceUsage = true
+
+	return applyPlanCmd
+}
+
+// runPlannedCommand runs the command of the plan with its args and flags, reading the desired state from the file passed.
+// The command is run directly rather than executing the root command again, whose flags are already parsed for apply-plan.
+// Its local flags are reset before being parsed, the global ones are left as they are set for apply-plan.
+func runPlannedCommand(rootCmd *cobra.Command, planned *plan.Plan, fromFile string) error {
+	plannedCmd, _, err := rootCmd.Find(planned.Command)
+	if err != nil || plannedCmd == rootCmd || plannedCmd.RunE == nil {
+		return &errors.ValidationError{Message: fmt.Sprintf("command '%s' of the plan is not known", strings.Join(planned.Command, " "))}
+	}
+
+	plannedCmd.LocalFlags().VisitAll(resetFlag)
+
+	if err = plannedCmd.ParseFlags(planned.CommandArgs(fromFile)[len(planned.Command):]); err != nil {
+		return err
+	}
+
+	args := plannedCmd.Flags().Args()
+	if err = plannedCmd.ValidateArgs(args); err != nil {
+		return err
+	}
+
+	if plannedCmd.PreRunE != nil {
+		if err = plannedCmd.PreRunE(plannedCmd, args); err != nil {
+			return err
+		}
+	}
+
+	return plannedCmd.RunE(plannedCmd, args)
+}
+
+// verifyPlan checks that the update being run is the one planned, and that the resource on GoCD server was not modified since.
+func verifyPlan(existing, desired string) error {
+	if err := goCdPlan.Verify(existing, desired); err != nil {
+		return err
+	}
+
+	cliLogger.Info("resource on GoCD server is in the state it was while planning, applying the plan")
+
+	return nil
+}
+
+// savePlan saves the update as plan under --plan-out, along with the command and the flags to apply it.
+func (cfg *Config) savePlan(existing, desired, diff string) error {
+	if runningCommand == nil {
+		return &errors.CLIError{Message: "plan cannot be saved, command being run is not known"}
+	}
+
+	flags := make(map[string][]string)
+	runningCommand.LocalFlags().Visit(func(flag *pflag.Flag) {
+		if flag.Name == plan.FromFileFlag {
+			return
+		}
+
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			flags[flag.Name] = sliceValue.GetSlice()
+
+			return
+		}
+
+		flags[flag.Name] = []string{flag.Value.String()}
+	})
+
+	planned := &plan.Plan{
+		Command:   strings.Fields(runningCommand.CommandPath())[1:],
+		Args:      runningArgs,
+		Flags:     flags,
+		Server:    cfg.URL,
+		Existing:  existing,
+		Desired:   desired,
+		Diff:      diff,
+		CreatedAt: time.Now().UTC(),
+		Checked:   true,
+	}
+
+	if err := planned.Save(cfg.PlanOut); err != nil {
+		return err
+	}
+
+	cliLogger.Infof("plan saved to '%s', it could be applied using 'gocd-cli apply-plan %s'", cfg.PlanOut, cfg.PlanOut)
+
+	return errPlanSaved
+}
+
+func setRunningCommand(cmd *cobra.Command, args []string) {
+	runningCommand = cmd
+	runningArgs = args
+}
@@ -1,18 +1,14 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 type goCdPlugin struct {
@@ -124,17 +120,8 @@ func updatePluginSettingsCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &setting); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &setting); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &setting); err != nil {
+				return err
 			}
 
 			pluginSettingsFetched, err := client.GetPluginSettings(setting.ID)
@@ -148,22 +135,19 @@ func updatePluginSettingsCommand() *cobra.Command {
 				}
 			}
 
-			if len(setting.ETAG) == 0 {
-				setting.ETAG = pluginSettingsFetched.ETAG
+			if setting, err = rebaseResource(pluginSettingsFetched, setting); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "pipeline-settings", setting.ID)
 
-			existing, err := diffCfg.String(pluginSettingsFetched)
-			if err != nil {
-				return err
-			}
-
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
+			if err = cliCfg.CheckDiffAndAllow(pluginSettingsFetched, setting, object.String()); err != nil {
 				return err
 			}
 
-			response, err := client.UpdatePluginSettings(setting)
+			response, err := updateResource(pluginSettingsFetched, setting, func() (gocd.PluginSettings, error) {
+				return client.GetPluginSettings(setting.ID)
+			}, client.UpdatePluginSettings)
 			if err != nil {
 				return err
 			}
@@ -180,6 +164,8 @@ func updatePluginSettingsCommand() *cobra.Command {
 	updatePluginSettingsCmd.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a plugin setting for respective plugin doesn't already exist, run create")
 
+	setUpdateMergeHelp(updatePluginSettingsCmd)
+
 	return updatePluginSettingsCmd
 }
 
@@ -322,17 +308,8 @@ func createPluginSettings(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &setting); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &setting); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &setting); err != nil {
+		return err
 	}
 
 	response, err := client.CreatePluginSettings(setting)
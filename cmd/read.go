@@ -5,6 +5,9 @@ import (
 	"os"
 
 	"github.com/nikhilsbhat/common/content"
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-cli/pkg/schema"
 	"github.com/spf13/cobra"
 )
 
@@ -35,3 +38,15 @@ func readObject(cmd *cobra.Command) (content.Object, error) {
 
 	return obj, nil
 }
+
+// decodeObject decodes the object read in YAML or JSON into the value passed, after validating it against the schema of the value.
+// The fields unknown to the value fail the decoding along with their line and column, rather than being dropped silently.
+// The kind set on top of the manifests is ignored, so that the files saved by export could be passed as they are.
+func decodeObject(object content.Object, value interface{}) error {
+	objType := object.CheckFileType(cliLogger)
+	if objType != content.FileTypeYAML && objType != content.FileTypeJSON {
+		return &errors.UnknownObjectTypeError{Name: objType}
+	}
+
+	return schema.Decode([]byte(object), value, manifest.KindKey)
+}
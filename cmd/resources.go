@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+const (
+	resourcePipeline            = "pipeline"
+	resourcePipelineGroup       = "pipeline-group"
+	resourceEnvironment         = "environment"
+	resourceConfigRepo          = "config-repo"
+	resourceAgent               = "agent"
+	resourceRole                = "role"
+	resourceUser                = "user"
+	resourceElasticAgentProfile = "elastic-agent-profile"
+	resourceClusterProfile      = "cluster-profile"
+	resourceArtifactStore       = "artifact-store"
+	resourceAuthConfig          = "authorization-configuration"
+
+	completionCacheTTL = time.Minute
+)
+
+// resourceLister lists the names of all the resources of a kind present in GoCD.
+type resourceLister func() ([]string, error)
+
+// resourceListers holds the resourceLister of every kind of resource, add an entry here to support a new kind.
+var resourceListers = map[string]resourceLister{
+	resourcePipeline: func() ([]string, error) {
+		response, err := client.GetPipelines()
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(response.Pipeline))
+		for _, pipeline := range response.Pipeline {
+			names = append(names, pipeline.Name)
+		}
+
+		return names, nil
+	},
+	resourcePipelineGroup: func() ([]string, error) {
+		response, err := client.GetPipelineGroups()
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(response))
+		for _, pipelineGroup := range response {
+			names = append(names, pipelineGroup.Name)
+		}
+
+		return names, nil
+	},
+	resourceEnvironment: func() ([]string, error) {
+		response, err := client.GetEnvironments()
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(response))
+		for _, environment := range response {
+			names = append(names, environment.Name)
+		}
+
+		return names, nil
+	},
+	resourceConfigRepo: func() ([]string, error) {
+		response, err := client.GetConfigRepos()
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(response))
+		for _, configRepo := range response {
+			names = append(names, configRepo.ID)
+		}
+
+		return names, nil
+	},
+	resourceAgent: func() ([]string, error) {
+		response, err := client.GetAgents()
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(response))
+		for _, agent := range response {
+			names = append(names, agent.Name)
+		}
+
+		return names, nil
+	},
+	resourceRole: func() ([]string, error) {
+		response, err := client.GetRoles()
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(response.Role))
+		for _, role := range response.Role {
+			names = append(names, role.Name)
+		}
+
+		return names, nil
+	},
+	resourceUser: func() ([]string, error) {
+		response, err := client.GetUsers()
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(response))
+		for _, user := range response {
+			names = append(names, user.Name)
+		}
+
+		return names, nil
+	},
+	resourceElasticAgentProfile: func() ([]string, error) {
+		response, err := client.GetElasticAgentProfiles()
+		if err != nil {
+			return nil, err
+		}
+
+		return getCommonConfigIDs(response.CommonConfigs), nil
+	},
+	resourceClusterProfile: func() ([]string, error) {
+		response, err := client.GetClusterProfiles()
+		if err != nil {
+			return nil, err
+		}
+
+		return getCommonConfigIDs(response.ClusterProfilesConfig), nil
+	},
+	resourceArtifactStore: func() ([]string, error) {
+		response, err := client.GetArtifactStores()
+		if err != nil {
+			return nil, err
+		}
+
+		return getCommonConfigIDs(response.CommonConfigs), nil
+	},
+	resourceAuthConfig: func() ([]string, error) {
+		response, err := client.GetAuthConfigs()
+		if err != nil {
+			return nil, err
+		}
+
+		return getCommonConfigIDs(response), nil
+	},
+}
+
+// completeResourceArgs returns the function completing the first argument of a command with the names of the resources of the kind passed.
+func completeResourceArgs(kind string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return completeResourceNames(kind)(cmd, args, toComplete)
+	}
+}
+
+// completeResourceNames returns the function completing a flag or argument with the names of the resources of the kind passed.
+// The names are fetched from GoCD server and cached under $HOME/.gocd/cache/completions for a minute, so that pressing Tab stays fast.
+func completeResourceNames(kind string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, err := getResourceNames(cmd, kind)
+		if err != nil {
+			cobra.CompErrorln(fmt.Sprintf("listing %s names errored with: %v", kind, err))
+
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return filterByPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeContextNames completes the argument with the names of the contexts cached under $HOME/.gocd/contexts.yaml.
+func completeContextNames(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	SetLogger(cliCfg.LogLevel)
+
+	_, contextsCfg, err := loadContexts()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return filterByPrefix(contextsCfg.Names(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerFlagCompletion registers the completion function for the flag, it fails only when the flag is not defined.
+func registerFlagCompletion(cmd *cobra.Command, flagName string, completion func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)) {
+	if err := cmd.RegisterFlagCompletionFunc(flagName, completion); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func getResourceNames(cmd *cobra.Command, kind string) ([]string, error) {
+	lister, ok := resourceListers[kind]
+	if !ok {
+		return nil, &errors.CLIError{Message: fmt.Sprintf("listing resources of kind '%s' is not supported", kind)}
+	}
+
+	cliCfg.nonInteractive = true
+
+	// the completion cache is looked up before setting the client, so that pressing Tab does not run the credential helper or start the gateway,
+	// and the output is not set up at all, since the names are written by cobra rather than the renderer.
+	reuseClient, err := setCLIConfig(cmd, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceCache := getCompletionCache()
+	cacheKey := strings.Join([]string{cliCfg.URL, cliCfg.Auth.UserName, kind}, "|")
+
+	var names []string
+	if resourceCache != nil && resourceCache.Get(cacheKey, &names) {
+		cliLogger.Debugf("names of %s found in completion cache", kind)
+
+		return names, nil
+	}
+
+	if !reuseClient {
+		if err = setGoCDClient(); err != nil {
+			return nil, err
+		}
+	}
+
+	names, err = lister()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	if resourceCache != nil {
+		if err = resourceCache.Set(cacheKey, names); err != nil {
+			cliLogger.Debugf("caching names of %s errored with '%v'", kind, err)
+		}
+	}
+
+	return names, nil
+}
+
+func getCommonConfigIDs(commonConfigs []gocd.CommonConfig) []string {
+	ids := make([]string, 0, len(commonConfigs))
+	for _, commonConfig := range commonConfigs {
+		ids = append(ids, commonConfig.ID)
+	}
+
+	return ids
+}
+
+func filterByPrefix(names []string, prefix string) []string {
+	filtered := make([]string, 0, len(names))
+
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			filtered = append(filtered, name)
+		}
+	}
+
+	return filtered
+}
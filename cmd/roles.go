@@ -1,16 +1,11 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/goccy/go-yaml"
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-cli/pkg/query"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
@@ -110,11 +105,12 @@ func getRolesCommand() *cobra.Command {
 
 func getRoleCommand() *cobra.Command {
 	getRoleCmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET a specific role in GoCD [https://api.gocd.org/current/#get-a-role]",
-		Example: "gocd-cli role get sample-config",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get",
+		Short:             "Command to GET a specific role in GoCD [https://api.gocd.org/current/#get-a-role]",
+		Example:           "gocd-cli role get sample-config",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceRole),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetRole(args[0])
@@ -181,17 +177,8 @@ func updateRoleCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &roleCfg); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &roleCfg); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &roleCfg); err != nil {
+				return err
 			}
 
 			rolesFetched, err := client.GetRole(roleCfg.Name)
@@ -205,22 +192,19 @@ func updateRoleCommand() *cobra.Command {
 				}
 			}
 
-			if len(roleCfg.ETAG) == 0 {
-				roleCfg.ETAG = rolesFetched.ETAG
+			if roleCfg, err = rebaseResource(rolesFetched, roleCfg); err != nil {
+				return err
 			}
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "role", rolesFetched.Name)
 
-			existing, err := diffCfg.String(rolesFetched)
-			if err != nil {
+			if err = cliCfg.CheckDiffAndAllow(rolesFetched, roleCfg, object.String()); err != nil {
 				return err
 			}
 
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
-				return err
-			}
-
-			response, err := client.UpdateRole(roleCfg)
+			response, err := updateResource(rolesFetched, roleCfg, func() (gocd.Role, error) {
+				return client.GetRole(roleCfg.Name)
+			}, client.UpdateRole)
 			if err != nil {
 				return err
 			}
@@ -236,6 +220,8 @@ func updateRoleCommand() *cobra.Command {
 	updateRoleCmd.PersistentFlags().BoolVarP(&create, "create", "", false,
 		"if a role by this name doesn't already exist, run create")
 
+	setUpdateMergeHelp(updateRoleCmd)
+
 	return updateRoleCmd
 }
 
@@ -245,23 +231,15 @@ func deleteRoleCommand() *cobra.Command {
 		Short: "Command to DELETE a specific role present in GoCD [https://api.gocd.org/current/#delete-a-role]",
 		Example: `gocd-cli role delete sample-config
 gocd-cli role delete sample-config -y`,
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceRole),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			roleName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete role '%s' [y/n]", roleName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteRole(roleName); err != nil {
@@ -320,17 +298,8 @@ func createRole(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &roleCfg); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &roleCfg); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &roleCfg); err != nil {
+		return err
 	}
 
 	response, err := client.CreateRole(roleCfg)
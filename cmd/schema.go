@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-cli/pkg/schema"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/spf13/cobra"
+	"github.com/thoas/go-funk"
+)
+
+const (
+	schemaFilePermission = 0o644
+	schemaDirPermission  = 0o755
+	schemaFileExtension  = ".json"
+)
+
+// schemaKinds are the kinds of the objects accepted as input by the create/update commands, and the values their schemas are generated from.
+// The kinds of the manifests are included, so that the schemas could be looked up by either.
+var schemaKinds = map[string]interface{}{
+	manifest.KindAgent:               gocd.Agent{},
+	"ArtifactInfo":                   gocd.ArtifactInfo{},
+	manifest.KindArtifactStore:       gocd.CommonConfig{},
+	manifest.KindAuthConfig:          gocd.CommonConfig{},
+	manifest.KindBackupConfig:        gocd.BackupConfig{},
+	manifest.KindClusterProfile:      gocd.CommonConfig{},
+	"CommonConfig":                   gocd.CommonConfig{},
+	manifest.KindConfigRepo:          gocd.ConfigRepo{},
+	manifest.KindElasticAgentProfile: gocd.CommonConfig{},
+	manifest.KindEnvironment:         gocd.Environment{},
+	manifest.KindMailServer:          gocd.MailServerConfig{},
+	manifest.KindPipeline:            gocd.PipelineConfig{},
+	"PipelineConfig":                 gocd.PipelineConfig{},
+	manifest.KindPipelineGroup:       gocd.PipelineGroup{},
+	manifest.KindPluginSettings:      gocd.PluginSettings{},
+	manifest.KindRole:                gocd.Role{},
+	manifest.KindSchedule:            gocd.Schedule{},
+	manifest.KindSiteURL:             gocd.SiteURLConfig{},
+	manifest.KindUser:                gocd.User{},
+}
+
+var schemaDir string
+
+func registerSchemaCommand() *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema [kind]",
+		Short: "Command to print the JSON Schema of the objects accepted as input, so that editors could validate the files passed via --from-file",
+		Long: fmt.Sprintf(`Command to print the JSON Schema of the objects accepted as input, so that editors could validate the files passed via --from-file.
+The schema is printed in JSON by default, and in YAML with '-o yaml'. With --dir, the schemas of all the kinds are written under it as <kind>.json.
+
+The objects passed to the create/update commands and the resources in the manifests are validated against the same schemas, the fields
+they do not declare fail the command along with their line and column, rather than being dropped silently. The kind set on top of the manifests
+is declared as an optional property, so that the manifests saved by 'export' are validated by the editors as well. Kinds supported are: %s.`, strings.Join(getSchemaKinds(), ", ")),
+		Example: `gocd-cli schema PipelineConfig
+gocd-cli schema config-repo -o yaml
+gocd-cli schema --dir .schemas
+// validate the files in editors using yaml-language-server, by adding the below comment on top of them
+# yaml-language-server: $schema=.schemas/PipelineConfig.json`,
+		Args:              cobra.RangeArgs(0, 1),
+		PreRunE:           setCLIClientWithoutCache,
+		ValidArgsFunction: completeSchemaArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(schemaDir) != 0 {
+				return writeSchemas(schemaDir, args)
+			}
+
+			if len(args) == 0 {
+				return cliRenderer.Render(strings.Join(getSchemaKinds(), "\n"))
+			}
+
+			kind, err := getSchemaKind(args[0])
+			if err != nil {
+				return err
+			}
+
+			objectSchema := getKindSchema(kind)
+
+			if cliCfg.yaml {
+				return cliRenderer.Render(objectSchema)
+			}
+
+			schemaJSON, err := json.MarshalIndent(objectSchema, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(schemaJSON))
+
+			return nil
+		},
+	}
+
+	schemaCmd.PersistentFlags().StringVarP(&schemaDir, "dir", "", "",
+		"directory under which the schemas are written as <kind>.json, the schemas of all the kinds are written when no kind is passed")
+
+	schemaCmd.SetUsageTemplate(getUsageTemplate())
+	schemaCmd.SilenceUsage = true
+
+	return schemaCmd
+}
+
+// writeSchemas writes the schemas of the kinds passed under the directory, the schemas of all the kinds are written when none is passed.
+func writeSchemas(dir string, kinds []string) error {
+	if len(kinds) == 0 {
+		kinds = getSchemaKinds()
+	}
+
+	if err := os.MkdirAll(dir, schemaDirPermission); err != nil {
+		return err
+	}
+
+	for _, kindArg := range kinds {
+		kind, err := getSchemaKind(kindArg)
+		if err != nil {
+			return err
+		}
+
+		schemaJSON, err := json.MarshalIndent(getKindSchema(kind), "", "  ")
+		if err != nil {
+			return err
+		}
+
+		file := filepath.Join(dir, kind+schemaFileExtension)
+		if err = os.WriteFile(file, append(schemaJSON, '\n'), schemaFilePermission); err != nil {
+			return err
+		}
+
+		cliLogger.Debugf("schema of '%s' was written to '%s'", kind, file)
+	}
+
+	return cliRenderer.Render(fmt.Sprintf("schemas of %d kind(s) were written under '%s'", len(kinds), dir))
+}
+
+// getKindSchema returns the schema of the kind, declaring the kinds of the manifests holding the objects of the kind on top of them.
+// ex: Pipeline for PipelineConfig, and AuthConfig, ClusterProfile and so on for CommonConfig.
+func getKindSchema(kind string) *schema.Schema {
+	objectSchema := schema.Generate(kind, schemaKinds[kind])
+
+	if funk.ContainsString(manifest.Kinds(), kind) {
+		objectSchema.SetKind(manifest.KindKey, kind)
+
+		return objectSchema
+	}
+
+	manifestKinds := make([]string, 0)
+
+	for _, manifestKind := range manifest.Kinds() {
+		if value, ok := schemaKinds[manifestKind]; ok && reflect.TypeOf(value) == reflect.TypeOf(schemaKinds[kind]) {
+			manifestKinds = append(manifestKinds, manifestKind)
+		}
+	}
+
+	objectSchema.SetKind(manifest.KindKey, manifestKinds...)
+
+	return objectSchema
+}
+
+// getSchemaKind returns the kind supported for the one passed, matched case-insensitively and ignoring hyphens. ex: config-repo for ConfigRepo.
+func getSchemaKind(kind string) (string, error) {
+	for _, supportedKind := range getSchemaKinds() {
+		if strings.EqualFold(strings.ReplaceAll(kind, "-", ""), supportedKind) {
+			return supportedKind, nil
+		}
+	}
+
+	return "", &errors.ValidationError{Message: fmt.Sprintf("kind '%s' is not supported, it should be one of %s", kind, strings.Join(getSchemaKinds(), "|"))}
+}
+
+func getSchemaKinds() []string {
+	kinds := make([]string, 0, len(schemaKinds))
+	for kind := range schemaKinds {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+func completeSchemaArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return filterByPrefix(getSchemaKinds(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
@@ -1,16 +1,11 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 func registerServerConfigCommand() *cobra.Command {
@@ -365,17 +360,8 @@ func createUpdateMailServerConfigCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &mailConfig); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &mailConfig); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &mailConfig); err != nil {
+				return err
 			}
 
 			mailServerConfigFetched, err := client.GetMailServerConfig()
@@ -385,12 +371,7 @@ func createUpdateMailServerConfigCommand() *cobra.Command {
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "mail-server-config", "")
 
-			existing, err := diffCfg.String(mailServerConfigFetched)
-			if err != nil {
-				return err
-			}
-
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
+			if err = cliCfg.CheckDiffAndAllow(mailServerConfigFetched, mailConfig, object.String()); err != nil {
 				return err
 			}
 
@@ -415,17 +396,8 @@ func deleteMailServerConfigCommand() *cobra.Command {
 		RunE: func(_ *cobra.Command, _ []string) error {
 			cliShellReadConfig.ShellMessage = "do you want to delete GoCD's mail server config [y/n]"
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteMailServerConfig(); err != nil {
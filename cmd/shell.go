@@ -0,0 +1,573 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// goCdShell is the session of 'gocd-cli shell', it is nil when the cli is not running as a shell.
+var goCdShell *shellSession
+
+// shellConnectionFlags are the global flags that change how the client connects to GoCD server,
+// commands passing any of these do not reuse the client of the shell session.
+var shellConnectionFlags = []string{
+	"server-url", "username", "password", "auth-token", "no-auth", "credential-helper", "profile", "skip-cache-config",
+	"ca-file-path", "client-cert-path", "client-key-path", "insecure-skip-verify", "proxy-url", "no-proxy",
+	"api-log-level", "api-retry-count", "api-retry-interval", "record", "replay", "cache-ttl", "no-cache", "no-history", "save-versions", "cache-credentials", "dry-run",
+}
+
+type shellSession struct {
+	baseCfg    Config
+	cfg        Config
+	defaults   map[string]string
+	profile    string
+	history    []string
+	client     gocd.GoCd
+	gateway    *transport.Gateway
+	dryRun     *transport.DryRun
+	connecting bool
+}
+
+func registerShellCommand() *cobra.Command {
+	shellCmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Command to start an interactive shell, that runs gocd-cli commands over a single authenticated session",
+		Long: `Command to start an interactive shell, that accepts gocd-cli commands without the 'gocd-cli' prefix.
+The client is set up once when the shell starts, and is reused by every command run in the shell,
+unless the command passes flags that change how to connect to GoCD server, ex: --server-url or --profile.
+
+Along with gocd-cli commands, the shell understands below commands:
+  set <flag> <value>    sets the global flag for all the commands run in the session, ex: set output table
+  unset <flag>          removes the global flag set using 'set'
+  use profile <name>    switches the session to the profile (context) passed, ex: use profile central
+  history               lists the commands run in the session
+  exit, quit            exits the shell, so does Ctrl-D
+
+Pressing Tab completes the commands, flags and names of the resources, up and down arrows walk through the history of the session.`,
+		Example: `gocd-cli shell
+gocd-cli shell --profile central -o yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if goCdShell != nil {
+				return &errors.CLIError{Message: "shell cannot be started from within the shell"}
+			}
+
+			goCdShell = &shellSession{baseCfg: cliCfg, defaults: make(map[string]string)}
+			defer goCdShell.close()
+
+			if err := goCdShell.connect(cmd.Root()); err != nil {
+				return err
+			}
+
+			return goCdShell.run(cmd.Root())
+		},
+	}
+
+	shellCmd.SetUsageTemplate(getUsageTemplate())
+	shellCmd.SilenceUsage = true
+
+	return shellCmd
+}
+
+// canReuseClient returns true if the command could use the client of the shell session, instead of setting up a new one.
+func (session *shellSession) canReuseClient(cmd *cobra.Command) bool {
+	if session.connecting || session.client == nil {
+		return false
+	}
+
+	flags := cmd.Root().PersistentFlags()
+	for _, flagName := range shellConnectionFlags {
+		if flag := flags.Lookup(flagName); flag != nil && flag.Changed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ownsGateway returns true if the gateway is the one used by the shell session, and should not be closed by the commands.
+func (session *shellSession) ownsGateway(gateway *transport.Gateway) bool {
+	return session != nil && session.gateway == gateway
+}
+
+// connect sets up the client of the session from the flags the shell was started with and the defaults set in the session.
+// When it fails, the session continues with the client it had.
+func (session *shellSession) connect(root *cobra.Command) error {
+	resetFlags(root)
+
+	cliCfg = session.baseCfg
+
+	flags := root.PersistentFlags()
+	for flagName, value := range session.defaults {
+		if err := flags.Set(flagName, value); err != nil {
+			return err
+		}
+	}
+
+	session.connecting = true
+	defer func() {
+		session.connecting = false
+	}()
+
+	if err := setCLIClient(root, nil); err != nil {
+		session.restore()
+
+		return err
+	}
+
+	if session.gateway != nil && session.gateway != goCdGateway {
+		if err := session.gateway.Close(); err != nil {
+			cliLogger.Debugf("closing loopback gateway of the previous session errored with '%v'", err)
+		}
+	}
+
+	session.cfg = cliCfg
+	session.client = client
+	session.gateway = goCdGateway
+	session.dryRun = goCdDryRun
+	session.profile = session.cfg.getProfileName()
+
+	return nil
+}
+
+// restore brings back the configurations and the client of the session, once a command is run.
+func (session *shellSession) restore() {
+	if goCdGateway != nil && goCdGateway != session.gateway {
+		if err := goCdGateway.Close(); err != nil && cliLogger != nil {
+			cliLogger.Debugf("closing loopback gateway errored with '%v'", err)
+		}
+	}
+
+	cliCfg = session.cfg
+	client = session.client
+	goCdGateway = session.gateway
+	goCdDryRun = session.dryRun
+}
+
+func (session *shellSession) close() {
+	if session.gateway != nil {
+		_ = session.gateway.Close()
+	}
+
+	goCdShell = nil
+}
+
+func (session *shellSession) run(root *cobra.Command) error {
+	stdinFd := int(os.Stdin.Fd())
+
+	if !term.IsTerminal(stdinFd) {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if exit := session.execute(root, scanner.Text()); exit {
+				return nil
+			}
+		}
+
+		return scanner.Err()
+	}
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, session.prompt())
+	terminal.AutoCompleteCallback = session.autoComplete(root)
+
+	for {
+		state, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return err
+		}
+
+		if width, height, err := term.GetSize(stdinFd); err == nil && width > 0 {
+			_ = terminal.SetSize(width, height)
+		}
+
+		terminal.SetPrompt(session.prompt())
+
+		line, readErr := terminal.ReadLine()
+
+		if err = term.Restore(stdinFd, state); err != nil {
+			return err
+		}
+
+		if readErr != nil {
+			if goerrors.Is(readErr, io.EOF) {
+				fmt.Println()
+
+				return nil
+			}
+
+			return readErr
+		}
+
+		if exit := session.execute(root, line); exit {
+			return nil
+		}
+	}
+}
+
+// execute runs the line entered in the shell, it returns true when the shell should exit.
+func (session *shellSession) execute(root *cobra.Command, line string) bool {
+	args, err := splitShellWords(line)
+	if err != nil {
+		session.report(root, err)
+
+		return false
+	}
+
+	if len(args) != 0 && args[0] == root.Name() {
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		return false
+	}
+
+	session.history = append(session.history, strings.TrimSpace(line))
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "history":
+		for index, command := range session.history {
+			fmt.Printf("%4d  %s\n", index+1, command)
+		}
+	case "set":
+		err = session.set(root, args[1:])
+	case "unset":
+		err = session.unset(root, args[1:])
+	case "use":
+		err = session.use(root, args[1:])
+	default:
+		err = session.runCommand(root, args)
+	}
+
+	if err != nil {
+		session.report(root, err)
+	}
+
+	return false
+}
+
+// report reports the error the way gocd-cli does, honouring the output format set in the session.
+func (session *shellSession) report(root *cobra.Command, err error) {
+	resetFlags(root)
+
+	cliCfg = session.cfg
+	defer session.restore()
+
+	_ = session.defaultFlags(root)
+
+	reportError(err)
+}
+
+func (session *shellSession) runCommand(root *cobra.Command, args []string) error {
+	resetFlags(root)
+
+	cliCfg = session.cfg
+
+	defer session.restore()
+
+	if err := session.defaultFlags(root); err != nil {
+		return err
+	}
+
+	registerExtensionCommands(root, args)
+	root.SetArgs(args)
+
+	_, err := root.ExecuteC()
+
+	return commandResult(err)
+}
+
+func (session *shellSession) set(root *cobra.Command, args []string) error {
+	if len(args) != 2 { //nolint:mnd
+		return &errors.ValidationError{Message: "set expects the name of a global flag and its value, ex: set output table"}
+	}
+
+	flagName := strings.TrimPrefix(args[0], "--")
+
+	flag := root.PersistentFlags().Lookup(flagName)
+	if flag == nil {
+		return &errors.ValidationError{Message: fmt.Sprintf("'%s' is not a global flag of %s", flagName, root.Name())}
+	}
+
+	if isShellConnectionFlag(flagName) {
+		previous, found := session.defaults[flagName]
+		session.defaults[flagName] = args[1]
+
+		if err := session.connect(root); err != nil {
+			session.revertDefault(flagName, previous, found)
+
+			return err
+		}
+
+		return nil
+	}
+
+	resetFlags(root)
+
+	cliCfg = session.cfg
+	defer session.restore()
+
+	if err := flag.Value.Set(args[1]); err != nil {
+		return &errors.ValidationError{Message: fmt.Sprintf("invalid value '%s' for flag '%s': %v", args[1], flagName, err)}
+	}
+
+	session.defaults[flagName] = args[1]
+
+	return nil
+}
+
+func (session *shellSession) unset(root *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return &errors.ValidationError{Message: "unset expects the name of a global flag, ex: unset output"}
+	}
+
+	flagName := strings.TrimPrefix(args[0], "--")
+
+	previous, found := session.defaults[flagName]
+	if !found {
+		return nil
+	}
+
+	delete(session.defaults, flagName)
+
+	if !isShellConnectionFlag(flagName) {
+		return nil
+	}
+
+	if err := session.connect(root); err != nil {
+		session.revertDefault(flagName, previous, found)
+
+		return err
+	}
+
+	return nil
+}
+
+func (session *shellSession) use(root *cobra.Command, args []string) error {
+	if len(args) != 2 || (args[0] != "profile" && args[0] != "context") { //nolint:mnd
+		return &errors.ValidationError{Message: "use expects the name of the profile, ex: use profile central"}
+	}
+
+	return session.set(root, []string{"profile", args[1]})
+}
+
+func (session *shellSession) revertDefault(flagName, previous string, found bool) {
+	if found {
+		session.defaults[flagName] = previous
+
+		return
+	}
+
+	delete(session.defaults, flagName)
+}
+
+// defaultFlags sets the non connection flags set using 'set', they are reset before running every command.
+func (session *shellSession) defaultFlags(root *cobra.Command) error {
+	flags := root.PersistentFlags()
+
+	for flagName, value := range session.defaults {
+		if isShellConnectionFlag(flagName) {
+			continue
+		}
+
+		if err := flags.Set(flagName, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (session *shellSession) prompt() string {
+	return fmt.Sprintf("gocd-cli (%s)> ", session.profile)
+}
+
+// autoComplete completes the word under the cursor on Tab, using the same completions gocd-cli offers to bash/zsh/fish.
+// When there is more than one completion, they are listed above the prompt.
+func (session *shellSession) autoComplete(root *cobra.Command) func(line string, pos int, key rune) (string, int, bool) {
+	return func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+
+		lineBeforeCursor := line[:pos]
+		partial := lineBeforeCursor[strings.LastIndex(lineBeforeCursor, " ")+1:]
+
+		words, err := splitShellWords(strings.TrimSuffix(lineBeforeCursor, partial))
+		if err != nil {
+			return "", 0, false
+		}
+
+		if len(words) != 0 && words[0] == root.Name() {
+			words = words[1:]
+		}
+
+		candidates := session.complete(root, words, partial)
+		if len(candidates) == 0 {
+			return "", 0, false
+		}
+
+		completion := longestCommonPrefix(candidates)
+		if len(candidates) == 1 {
+			completion += " "
+		}
+
+		if len(candidates) > 1 && completion == partial {
+			fmt.Fprintf(os.Stdout, "\r\n%s\r\n%s%s", strings.Join(candidates, "  "), session.prompt(), lineBeforeCursor)
+
+			return line, pos, true
+		}
+
+		return strings.TrimSuffix(lineBeforeCursor, partial) + completion + line[pos:], pos - len(partial) + len(completion), true
+	}
+}
+
+// complete runs the hidden completion command of cobra, and returns the completions identified by it.
+func (session *shellSession) complete(root *cobra.Command, words []string, partial string) []string {
+	var output bytes.Buffer
+
+	resetFlags(root)
+
+	cliCfg = session.cfg
+
+	defer func() {
+		root.SetOut(nil)
+		root.SetErr(nil)
+		session.restore()
+	}()
+
+	root.SetOut(&output)
+	root.SetErr(io.Discard)
+	root.SetArgs(append(append([]string{cobra.ShellCompRequestCmd}, words...), partial))
+
+	if _, err := root.ExecuteC(); err != nil {
+		return nil
+	}
+
+	candidates := make([]string, 0)
+
+	for _, completion := range strings.Split(output.String(), "\n") {
+		if len(completion) == 0 || strings.HasPrefix(completion, ":") {
+			continue
+		}
+
+		candidates = append(candidates, strings.SplitN(completion, "\t", 2)[0]) //nolint:mnd
+	}
+
+	return candidates
+}
+
+// resetFlags resets the flags set by the previous command run in the shell, so that they do not leak to the next one.
+func resetFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(resetFlag)
+	cmd.PersistentFlags().VisitAll(resetFlag)
+
+	for _, subCommand := range cmd.Commands() {
+		resetFlags(subCommand)
+	}
+}
+
+// resetFlag sets the flag back to its default value, as if it was never set.
+func resetFlag(flag *pflag.Flag) {
+	if !flag.Changed {
+		return
+	}
+
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		values := make([]string, 0)
+		if defaultValue := strings.Trim(flag.DefValue, "[]"); len(defaultValue) != 0 {
+			values = strings.Split(defaultValue, ",")
+		}
+
+		_ = sliceValue.Replace(values)
+	} else {
+		_ = flag.Value.Set(flag.DefValue)
+	}
+
+	flag.Changed = false
+}
+
+func isShellConnectionFlag(flagName string) bool {
+	for _, connectionFlag := range shellConnectionFlags {
+		if connectionFlag == flagName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitShellWords splits the line into words the way a shell does, honouring the quotes and escapes.
+func splitShellWords(line string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, char := range line {
+		switch {
+		case escaped:
+			word.WriteRune(char)
+			escaped = false
+		case char == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				word.WriteRune(char)
+			}
+		case char == '"' || char == '\'':
+			quote, inWord = char, true
+		case char == ' ' || char == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(char)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("unterminated quote or escape in '%s'", line)}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+func longestCommonPrefix(words []string) string {
+	prefix := words[0]
+
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ghodssYAML "github.com/ghodss/yaml"
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/skeleton"
+	"github.com/spf13/cobra"
+)
+
+func registerSkeletonCommand() *cobra.Command {
+	skeletonCmd := &cobra.Command{
+		Use:   "skeleton <kind>",
+		Short: "Command to print a template of the object that the create/update command of a kind accepts via --from-file",
+		Long: fmt.Sprintf(`Command to print a template of the object that the create/update command of a kind accepts via --from-file.
+The template is printed in YAML along with comments describing the fields, and in JSON without them with '-o json'.
+It holds the fields that are required or commonly set, with example values to be replaced.
+
+Kinds supported are: %s, they could also be passed in lower case with hyphens, ex: config-repo.`, strings.Join(getSkeletonKinds(), ", ")),
+		Example: `gocd-cli skeleton config-repo > config-repo.yaml
+gocd-cli skeleton ElasticAgentProfile -o json
+gocd-cli skeleton pipeline > pipeline.yaml && gocd-cli pipeline create --from-file pipeline.yaml
+gocd-cli skeleton schedule > schedule.yaml && gocd-cli pipeline schedule sample-pipeline --from-file schedule.yaml`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           setCLIClientWithoutCache,
+		ValidArgsFunction: completeSkeletonArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			kind, err := getSkeletonKind(args[0])
+			if err != nil {
+				return err
+			}
+
+			out, err := renderSkeleton(kind, cliCfg.json)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(out)
+
+			return nil
+		},
+	}
+
+	skeletonCmd.SetUsageTemplate(getUsageTemplate())
+	skeletonCmd.SilenceUsage = true
+
+	return skeletonCmd
+}
+
+// renderSkeleton returns the template of the kind in YAML, or in JSON without the comments.
+func renderSkeleton(kind string, asJSON bool) (string, error) {
+	objectSkeleton, _ := skeleton.Get(kind)
+
+	header := fmt.Sprintf("# %s, the input of 'gocd-cli %s --from-file'.\n", kind, objectSkeleton.Command)
+	if _, ok := schemaKinds[kind]; ok {
+		header += fmt.Sprintf("# validate it in editors with the schema printed by 'gocd-cli schema %s'.\n", kind)
+	}
+
+	if !asJSON {
+		return header + objectSkeleton.Template, nil
+	}
+
+	skeletonJSON, err := ghodssYAML.YAMLToJSON([]byte(objectSkeleton.Template))
+	if err != nil {
+		return "", err
+	}
+
+	var indented bytes.Buffer
+	if err = json.Indent(&indented, skeletonJSON, "", "  "); err != nil {
+		return "", err
+	}
+
+	return indented.String() + "\n", nil
+}
+
+// getSkeletonKind returns the kind supported for the one passed, matched case-insensitively and ignoring hyphens. ex: config-repo for ConfigRepo.
+func getSkeletonKind(kind string) (string, error) {
+	for _, supportedKind := range getSkeletonKinds() {
+		if strings.EqualFold(strings.ReplaceAll(kind, "-", ""), supportedKind) {
+			return supportedKind, nil
+		}
+	}
+
+	return "", &errors.ValidationError{Message: fmt.Sprintf("kind '%s' is not supported, it should be one of %s", kind, strings.Join(getSkeletonKinds(), "|"))}
+}
+
+func getSkeletonKinds() []string {
+	return skeleton.Kinds()
+}
+
+func completeSkeletonArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return filterByPrefix(getSkeletonKinds(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
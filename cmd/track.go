@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/gitrepo"
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+const defaultTrackInterval = 10 * time.Minute
+
+var (
+	trackRepo     string
+	trackKinds    []string
+	trackInterval time.Duration
+	trackOnce     bool
+)
+
+func registerTrackCommand() *cobra.Command {
+	trackCmd := &cobra.Command{
+		Use:   "track",
+		Short: "Command to TRACK the configuration of GoCD server continuously, by committing its snapshots to a git repository",
+		Long: fmt.Sprintf(`Command to TRACK the configuration of GoCD server continuously, by committing its snapshots to a git repository.
+Every --interval, the resources managed through GoCD server are exported under --repo in the same layout as 'export',
+and a commit is made whenever they changed, with a message summarising the resources added, modified and removed.
+This gives an audit trail of the changes made to GoCD, including the ones made through its UI, the time of a commit is when the change was detected.
+
+The repository is initialised when --repo is not one already. Failing to fetch the resources is logged and retried on the next interval.
+The snapshots are always fetched from GoCD server, the responses cached by --cache-ttl are not used for them.
+The resources defined in config repos are not tracked. Kinds supported are: %s.`, strings.Join(manifest.Kinds(), ", ")),
+		Example: `gocd-cli track --repo ./gocd-history --interval 10m
+gocd-cli track --repo ./gocd-history --kind Pipeline --kind Environment --once`,
+		Args:    cobra.NoArgs,
+		PreRunE: setTrackClient,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if !trackOnce && trackInterval <= 0 {
+				return &errors.ValidationError{Message: fmt.Sprintf("--interval should be greater than zero, got '%s'", trackInterval)}
+			}
+
+			if _, err := getManifestKinds(trackKinds); err != nil {
+				return err
+			}
+
+			repo, err := gitrepo.Init(trackRepo)
+			if err != nil {
+				return err
+			}
+
+			for {
+				objects, err := fetchManifestObjects(trackKinds)
+				if err != nil {
+					if trackOnce {
+						return err
+					}
+
+					cliLogger.Errorf("taking snapshot of GoCD server errored, would be retried in '%s': %v", trackInterval, err)
+				} else if err = commitSnapshot(repo, objects); err != nil {
+					return err
+				}
+
+				if trackOnce {
+					break
+				}
+
+				time.Sleep(trackInterval)
+			}
+
+			return nil
+		},
+	}
+
+	registerManifestKindFlag(trackCmd, &trackKinds, "kinds of the resources to be tracked, defaults to all the kinds supported")
+
+	trackCmd.PersistentFlags().StringVarP(&trackRepo, "repo", "", "",
+		"git repository under which the snapshots are committed, it is initialised if it is not one already")
+	trackCmd.PersistentFlags().DurationVarP(&trackInterval, "interval", "", defaultTrackInterval,
+		"time interval between the snapshots, it should be greater than zero, ex: 30s, 10m")
+	trackCmd.PersistentFlags().BoolVarP(&trackOnce, "once", "", false,
+		"set this to take a single snapshot and exit, ex: when run from cron")
+
+	if err := trackCmd.MarkPersistentFlagRequired("repo"); err != nil {
+		cliLogger.Fatalf("%v", err)
+	}
+
+	trackCmd.SetUsageTemplate(getUsageTemplate())
+	trackCmd.SilenceUsage = true
+
+	return trackCmd
+}
+
+// setTrackClient sets up the client of the track command, with the response cache disabled since the snapshots
+// should hold the resources as they are on GoCD server, not as they were when cached by --cache-ttl.
+func setTrackClient(cmd *cobra.Command, args []string) error {
+	reuseClient, err := setCLIConfig(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	// the client of the shell session is reused only when it does not cache the responses.
+	if !reuseClient || (cliCfg.CacheTTL > 0 && !cliCfg.NoCache) {
+		cliCfg.NoCache = true
+
+		if err = setGoCDClient(); err != nil {
+			return err
+		}
+	}
+
+	return setCLIOutput()
+}
+
+// commitSnapshot exports the resources under the repository, and commits them when they changed since the last snapshot.
+func commitSnapshot(repo *gitrepo.Repo, objects []manifestObject) error {
+	changed, err := exportManifests(repo.Dir(), trackKinds, objects)
+	if err != nil {
+		return err
+	}
+
+	committed, err := repo.Commit(getSnapshotMessage(changed))
+	if err != nil {
+		return err
+	}
+
+	if !committed {
+		cliLogger.Debugf("no changes to the resources on GoCD server since the last snapshot")
+
+		return nil
+	}
+
+	cliLogger.Infof("%d resource(s) changed on GoCD server, committed to '%s'", len(changed), repo.Dir())
+
+	return nil
+}
+
+// getSnapshotMessage returns the commit message summarising the resources changed, ex: "Modify Pipeline 'build'" for a single change,
+// and "Update 3 resources (1 added, 2 modified)" followed by a line per resource otherwise.
+func getSnapshotMessage(changed []exportedResource) string {
+	verbs := map[string]string{exportChangeAdded: "Add", exportChangeModified: "Modify", exportChangeRemoved: "Remove"}
+
+	var subject string
+
+	switch len(changed) {
+	case 0:
+		// the manifests are as they were, but were never committed. ex: exported before being tracked.
+		subject = "Snapshot the configuration of GoCD server"
+	case 1:
+		subject = fmt.Sprintf("%s %s '%s'", verbs[changed[0].Change], changed[0].Kind, changed[0].Name)
+	default:
+		counts := make([]string, 0, len(verbs))
+
+		for _, change := range []string{exportChangeAdded, exportChangeModified, exportChangeRemoved} {
+			var count int
+
+			for _, resource := range changed {
+				if resource.Change == change {
+					count++
+				}
+			}
+
+			if count != 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", count, change))
+			}
+		}
+
+		subject = fmt.Sprintf("Update %d resources (%s)", len(changed), strings.Join(counts, ", "))
+	}
+
+	lines := []string{subject, ""}
+	for _, resource := range changed {
+		lines = append(lines, fmt.Sprintf("- %s %s '%s'", resource.Change, resource.Kind, resource.Name))
+	}
+
+	if len(changed) != 0 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, fmt.Sprintf("Snapshot of GoCD server '%s' taken at %s.", cliCfg.URL, time.Now().UTC().Format(time.RFC3339)))
+
+	return strings.Join(lines, "\n")
+}
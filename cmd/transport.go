@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	goerrors "errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+var (
+	goCdGateway *transport.Gateway
+	goCdDryRun  *transport.DryRun
+	// errDryRunWithheld stops the commands whose next steps depend on the outcome of a call withheld by --dry-run,
+	// ex: waiting for the backup scheduled to complete. It is not reported as a failure.
+	errDryRunWithheld = goerrors.New("call withheld by dry run")
+	// readOnlyCommands are the names of the commands that only read from GoCD server, along with the prefixes of such names.
+	readOnlyCommands = []string{"get", "list", "show", "status", "find", "export", "compare", "drift", "track", "version", "who-am-i"}
+)
+
+// getGoCDClient returns the GoCD sdk client. When the configurations need a transport that the sdk cannot build by itself,
+// ex: mutual TLS or proxies, the client is pointed at a loopback gateway which forwards the calls using the custom transport.
+func (cfg *Config) getGoCDClient() (gocd.GoCd, error) {
+	middlewares, err := cfg.getTransportMiddlewares()
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.getTransportConfig().IsCustom() && len(middlewares) == 0 && len(cfg.Replay) == 0 {
+		return cfg.getDirectGoCDClient()
+	}
+
+	roundTripper, err := cfg.getRoundTripper(middlewares)
+	if err != nil {
+		return nil, err
+	}
+
+	if goCdGateway != nil && !goCdShell.ownsGateway(goCdGateway) {
+		if err = goCdGateway.Close(); err != nil {
+			cliLogger.Debugf("closing previous loopback gateway errored with '%v'", err)
+		}
+	}
+
+	goCdGateway, err = transport.NewGateway(cfg.URL, roundTripper)
+	if err != nil {
+		return nil, err
+	}
+
+	cliLogger.Debugf("calls to GoCD server '%s' would be routed via loopback gateway", cfg.URL)
+
+	return gocd.NewClient(goCdGateway.URL(), cfg.Auth, cfg.APILogLevel, nil), nil
+}
+
+// getTransportMiddlewares returns the middlewares that the calls to GoCD server should pass through, the first one being the outermost.
+func (cfg *Config) getTransportMiddlewares() ([]transport.Middleware, error) {
+	middlewares := make([]transport.Middleware, 0)
+
+	goCdDryRun = nil
+	if cfg.DryRun {
+		cliLogger.Debug("--dry-run is opted, calls that would modify GoCD server would be printed instead of being sent")
+
+		goCdDryRun = transport.NewDryRun(os.Stdout)
+		middlewares = append(middlewares, goCdDryRun.Middleware())
+	}
+
+	if len(cfg.Record) != 0 {
+		cliLogger.Debugf("--record is opted, calls to GoCD server would be saved as fixtures under '%s'", cfg.Record)
+
+		recorder, err := transport.Recorder(cfg.Record)
+		if err != nil {
+			return nil, err
+		}
+
+		middlewares = append(middlewares, recorder)
+	}
+
+	if cfg.CacheTTL > 0 && !cfg.NoCache && len(cfg.Replay) == 0 {
+		responseCache, err := getResponseCache(cfg.CacheTTL)
+		if err != nil {
+			return nil, err
+		}
+
+		cliLogger.Debugf("--cache-ttl is set, GET calls to GoCD server would be cached under '%s' for '%s'", responseCache.Dir(), cfg.CacheTTL)
+
+		middlewares = append(middlewares, transport.Cacher(responseCache))
+	}
+
+	if cfg.SaveVersions && len(cfg.Replay) == 0 {
+		versions, err := getVersionCache()
+		if err != nil {
+			return nil, err
+		}
+
+		cliLogger.Debugf("--save-versions is opted, resources fetched would be saved under '%s' for '%s'", versions.Dir(), versionCacheTTL)
+
+		middlewares = append(middlewares, transport.Versioner(versions))
+	}
+
+	// the commands that only read from GoCD server have nothing to save to the history, their calls are not routed via the gateway for it.
+	if !cfg.NoHistory && len(cfg.Replay) == 0 && !isReadOnlyCommand(runningCommand) {
+		journal, err := getJournal()
+		if err != nil {
+			cliLogger.Debugf("resources would not be saved to the history, as fetching user's home directory errored with '%v'", err)
+		} else {
+			cliLogger.Debugf("resources would be saved under '%s' before being modified, use --no-history to disable it", journal.Dir())
+
+			if _, err = journal.Prune(historyMaxEntries, historyMaxAge); err != nil {
+				cliLogger.Debugf("pruning the history under '%s' errored with '%v'", journal.Dir(), err)
+			}
+
+			middlewares = append(middlewares, journal.Middleware(cfg.getHistoryEntry, resolveHistoryResource))
+		}
+	}
+
+	return middlewares, nil
+}
+
+// isReadOnlyCommand returns true if the command only reads from GoCD server, it is identified by its name or the prefix of it. ex: get-all, list-store.
+func isReadOnlyCommand(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+
+	for _, name := range readOnlyCommands {
+		if cmd.Name() == name || strings.HasPrefix(cmd.Name(), name+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getRoundTripper returns the http.RoundTripper that makes the calls to GoCD server through the middlewares passed,
+// the calls are served from the fixtures under --replay when it is set.
+func (cfg *Config) getRoundTripper(middlewares []transport.Middleware) (http.RoundTripper, error) {
+	var (
+		roundTripper http.RoundTripper
+		err          error
+	)
+
+	if len(cfg.Replay) != 0 {
+		cliLogger.Debugf("--replay is opted, calls to GoCD server would be served from the fixtures under '%s'", cfg.Replay)
+
+		roundTripper, err = transport.Replayer(cfg.Replay)
+	} else {
+		roundTripper, err = transport.NewTransport(cfg.getTransportConfig())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.Chain(roundTripper, middlewares...), nil
+}
+
+func (cfg *Config) getDirectGoCDClient() (gocd.GoCd, error) {
+	if len(cfg.CaPath) == 0 {
+		return gocd.NewClient(cfg.URL, cfg.Auth, cfg.APILogLevel, nil), nil
+	}
+
+	cliLogger.Debug("CA based auth is enabled, hence reading CA from the path")
+
+	caAbs, err := filepath.Abs(cfg.CaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	caContent, err := os.ReadFile(caAbs)
+	if err != nil {
+		return nil, err
+	}
+
+	return gocd.NewClient(cfg.URL, cfg.Auth, cfg.APILogLevel, caContent), nil
+}
+
+func (cfg *Config) getTransportConfig() transport.Config {
+	return transport.Config{
+		CaPath:             cfg.CaPath,
+		ClientCertPath:     cfg.ClientCertPath,
+		ClientKeyPath:      cfg.ClientKeyPath,
+		InsecureSkipVerify: cfg.InsecureSkip,
+		ProxyURL:           cfg.ProxyURL,
+		NoProxy:            cfg.NoProxy,
+	}
+}
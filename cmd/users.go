@@ -1,18 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/nikhilsbhat/common/content"
-	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
 	"github.com/nikhilsbhat/gocd-sdk-go"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 func registerUsersCommand() *cobra.Command {
@@ -76,10 +71,11 @@ func usersGetCommand() *cobra.Command {
 
 func userGetCommand() *cobra.Command {
 	getUserCmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Command to GET user present in GoCD [https://api.gocd.org/current/#get-one-user]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "get",
+		Short:             "Command to GET user present in GoCD [https://api.gocd.org/current/#get-one-user]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceUser),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			for {
 				response, err := client.GetUser(args[0])
@@ -130,17 +126,8 @@ func userUpdateCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &user); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &user); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &user); err != nil {
+				return err
 			}
 
 			userFetched, err := client.GetUser(user.Name)
@@ -156,12 +143,7 @@ func userUpdateCommand() *cobra.Command {
 
 			cliShellReadConfig.ShellMessage = fmt.Sprintf(updateMessage, "user", user.Name)
 
-			existing, err := diffCfg.String(userFetched)
-			if err != nil {
-				return err
-			}
-
-			if err = cliCfg.CheckDiffAndAllow(existing, object.String()); err != nil {
+			if err = cliCfg.CheckDiffAndAllow(userFetched, user, object.String()); err != nil {
 				return err
 			}
 
@@ -182,25 +164,17 @@ func userUpdateCommand() *cobra.Command {
 
 func userDeleteCommand() *cobra.Command {
 	deleteUserCmd := &cobra.Command{
-		Use:     "delete",
-		Short:   "Command to DELETE user present in GoCD [https://api.gocd.org/current/#delete-a-user]",
-		Args:    cobra.RangeArgs(1, 1),
-		PreRunE: setCLIClient,
+		Use:               "delete",
+		Short:             "Command to DELETE user present in GoCD [https://api.gocd.org/current/#delete-a-user]",
+		Args:              cobra.RangeArgs(1, 1),
+		ValidArgsFunction: completeResourceArgs(resourceUser),
+		PreRunE:           setCLIClient,
 		RunE: func(_ *cobra.Command, args []string) error {
 			userName := args[0]
 			cliShellReadConfig.ShellMessage = fmt.Sprintf("do you want to delete user '%s' [y/n]", userName)
 
-			if !cliCfg.Yes {
-				contains, option := cliShellReadConfig.Reader()
-				if !contains {
-					cliLogger.Fatalln(inputValidationFailureMessage)
-				}
-
-				if option.Short == "n" {
-					cliLogger.Warn(optingOutMessage)
-
-					os.Exit(0)
-				}
+			if err := cliCfg.confirm(); err != nil {
+				return err
 			}
 
 			if err := client.DeleteUser(userName); err != nil {
@@ -227,17 +201,8 @@ func bulkDeleteUsersCommand() *cobra.Command {
 				return err
 			}
 
-			switch objType := object.CheckFileType(cliLogger); objType {
-			case content.FileTypeYAML:
-				if err = yaml.Unmarshal([]byte(object), &user); err != nil {
-					return err
-				}
-			case content.FileTypeJSON:
-				if err = json.Unmarshal([]byte(object), &user); err != nil {
-					return err
-				}
-			default:
-				return &errors.UnknownObjectTypeError{Name: objType}
+			if err = decodeObject(object, &user); err != nil {
+				return err
 			}
 
 			if err = client.BulkDeleteUsers(user); err != nil {
@@ -298,17 +263,8 @@ func createUser(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	switch objType := object.CheckFileType(cliLogger); objType {
-	case content.FileTypeYAML:
-		if err = yaml.Unmarshal([]byte(object), &user); err != nil {
-			return err
-		}
-	case content.FileTypeJSON:
-		if err = json.Unmarshal([]byte(object), &user); err != nil {
-			return err
-		}
-	default:
-		return &errors.UnknownObjectTypeError{Name: objType}
+	if err = decodeObject(object, &user); err != nil {
+		return err
 	}
 
 	_, err = client.CreateUser(user)
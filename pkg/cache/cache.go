@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	filePermission = 0o600
+	dirPermission  = 0o700
+	fileExtension  = ".json"
+)
+
+// Cache is an on-disk cache, that saves the values as JSON files under a directory for the TTL set.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+type entry struct {
+	Key       string          `json:"key,omitempty" yaml:"key,omitempty"`
+	ExpiresAt time.Time       `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+	Value     json.RawMessage `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// New returns new instance of Cache, that saves the values under dir for the duration of ttl.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		dir: dir,
+		ttl: ttl,
+	}
+}
+
+// Dir returns the directory under which the values are cached.
+func (cache *Cache) Dir() string {
+	return cache.dir
+}
+
+// Get reads the value cached against the key into value, it returns false if the key is not cached or has expired.
+func (cache *Cache) Get(key string, value interface{}) bool {
+	data, err := os.ReadFile(cache.path(key))
+	if err != nil {
+		return false
+	}
+
+	var cached entry
+	if err = json.Unmarshal(data, &cached); err != nil {
+		return false
+	}
+
+	if cached.Key != key || time.Now().After(cached.ExpiresAt) {
+		return false
+	}
+
+	return json.Unmarshal(cached.Value, value) == nil
+}
+
+// Set caches the value against the key.
+func (cache *Cache) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	cached, err := json.Marshal(entry{Key: key, ExpiresAt: time.Now().Add(cache.ttl), Value: data})
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(cache.dir, dirPermission); err != nil {
+		return err
+	}
+
+	return os.WriteFile(cache.path(key), cached, filePermission)
+}
+
+// Delete removes the value cached against the key.
+func (cache *Cache) Delete(key string) error {
+	if err := os.Remove(cache.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Prune removes the values that have expired, along with the files under the directory that are not readable as values cached.
+func (cache *Cache) Prune() error {
+	files, err := os.ReadDir(cache.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	now := time.Now()
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != fileExtension {
+			continue
+		}
+
+		path := filepath.Join(cache.dir, file.Name())
+
+		var cached entry
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &cached) == nil && now.Before(cached.ExpiresAt) {
+			continue
+		}
+
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Clear removes all the values cached.
+func (cache *Cache) Clear() error {
+	return os.RemoveAll(cache.dir)
+}
+
+func (cache *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(cache.dir, hex.EncodeToString(sum[:])+fileExtension)
+}
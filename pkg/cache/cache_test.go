@@ -0,0 +1,72 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("should be able to read the value that was cached", func(t *testing.T) {
+		resourceCache := cache.New(filepath.Join(t.TempDir(), "completions"), time.Minute)
+		require.NoError(t, resourceCache.Set("pipeline", []string{"sample-1", "sample-2"}))
+
+		var names []string
+		assert.True(t, resourceCache.Get("pipeline", &names))
+		assert.Equal(t, []string{"sample-1", "sample-2"}, names)
+	})
+
+	t.Run("should not return the values that are not cached", func(t *testing.T) {
+		resourceCache := cache.New(t.TempDir(), time.Minute)
+
+		var names []string
+		assert.False(t, resourceCache.Get("pipeline", &names))
+		assert.Nil(t, names)
+	})
+
+	t.Run("should not return the values that have expired", func(t *testing.T) {
+		resourceCache := cache.New(t.TempDir(), -time.Second)
+		require.NoError(t, resourceCache.Set("pipeline", []string{"sample-1"}))
+
+		var names []string
+		assert.False(t, resourceCache.Get("pipeline", &names))
+	})
+
+	t.Run("should not return the values once deleted or cleared", func(t *testing.T) {
+		resourceCache := cache.New(t.TempDir(), time.Minute)
+		require.NoError(t, resourceCache.Set("pipeline", []string{"sample-1"}))
+		require.NoError(t, resourceCache.Set("environment", []string{"production"}))
+
+		var names []string
+		require.NoError(t, resourceCache.Delete("pipeline"))
+		assert.False(t, resourceCache.Get("pipeline", &names))
+		assert.True(t, resourceCache.Get("environment", &names))
+
+		require.NoError(t, resourceCache.Clear())
+		assert.False(t, resourceCache.Get("environment", &names))
+		require.NoError(t, resourceCache.Delete("environment"))
+	})
+
+	t.Run("should remove only the values that have expired when pruned", func(t *testing.T) {
+		dir := t.TempDir()
+		expired := cache.New(dir, -time.Second)
+		require.NoError(t, expired.Set("pipeline", []string{"sample-1"}))
+
+		resourceCache := cache.New(dir, time.Minute)
+		require.NoError(t, resourceCache.Set("environment", []string{"production"}))
+		require.NoError(t, resourceCache.Prune())
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+
+		var names []string
+		assert.True(t, resourceCache.Get("environment", &names))
+		assert.NoError(t, cache.New(filepath.Join(dir, "missing"), time.Minute).Prune())
+	})
+}
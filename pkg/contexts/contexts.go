@@ -0,0 +1,300 @@
+package contexts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/thoas/go-funk"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultContext is the context used when neither --profile nor current-context is set.
+	DefaultContext = "default"
+	filePermission = 0o600
+	dirPermission  = 0o755
+)
+
+// Config holds the kubeconfig styled contexts file, that helps in switching between multiple GoCD servers.
+type Config struct {
+	CurrentContext string       `json:"current-context,omitempty" yaml:"current-context,omitempty"`
+	Servers        []Server     `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Credentials    []Credential `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	Contexts       []Context    `json:"contexts,omitempty" yaml:"contexts,omitempty"`
+}
+
+// Server holds the information of a GoCD server that a context can refer to.
+type Server struct {
+	Name               string `json:"name,omitempty" yaml:"name,omitempty"`
+	URL                string `json:"url,omitempty" yaml:"url,omitempty"`
+	CaPath             string `json:"ca_path,omitempty" yaml:"ca_path,omitempty"`
+	ClientCertPath     string `json:"client_cert_path,omitempty" yaml:"client_cert_path,omitempty"`
+	ClientKeyPath      string `json:"client_key_path,omitempty" yaml:"client_key_path,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+	ProxyURL           string `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`
+	NoProxy            string `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty"`
+}
+
+// Credential holds the authorization information that a context can refer to.
+type Credential struct {
+	Name             string `json:"name,omitempty" yaml:"name,omitempty"`
+	UserName         string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password         string `json:"password,omitempty" yaml:"password,omitempty"`
+	BearerToken      string `json:"bearer_token,omitempty" yaml:"bearer_token,omitempty"`
+	NoAuth           bool   `json:"no_auth,omitempty" yaml:"no_auth,omitempty"`
+	Encrypted        string `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
+	CredentialHelper string `json:"credential_helper,omitempty" yaml:"credential_helper,omitempty"`
+}
+
+// Context ties a server with the credential to be used while talking to it.
+type Context struct {
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"`
+	Server     string `json:"server,omitempty" yaml:"server,omitempty"`
+	Credential string `json:"credential,omitempty" yaml:"credential,omitempty"`
+}
+
+// Load reads the contexts file from the path specified, a missing file results in an empty Config.
+func Load(path string) (*Config, error) {
+	cfg := new(Config)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Save writes the Config to the path specified, creating the parent directory if required.
+func (cfg *Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), dirPermission); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, filePermission)
+}
+
+// Current returns the context that should be used when the name passed is empty.
+func (cfg *Config) Current(name string) string {
+	if len(name) != 0 {
+		return name
+	}
+
+	if len(cfg.CurrentContext) != 0 {
+		return cfg.CurrentContext
+	}
+
+	return DefaultContext
+}
+
+// Names returns the names of all contexts present in the Config.
+func (cfg *Config) Names() []string {
+	names := make([]string, 0, len(cfg.Contexts))
+	for _, context := range cfg.Contexts {
+		names = append(names, context.Name)
+	}
+
+	return names
+}
+
+// Set adds or updates the context by name along with the server and credential it refers to.
+// Both server and credential are stored under the name of the context.
+func (cfg *Config) Set(name string, server Server, credential Credential) {
+	server.Name = name
+	credential.Name = name
+
+	cfg.setServer(server)
+	cfg.setCredential(credential)
+
+	context := Context{Name: name, Server: name, Credential: name}
+	if index := cfg.contextIndex(name); index >= 0 {
+		cfg.Contexts[index] = context
+	} else {
+		cfg.Contexts = append(cfg.Contexts, context)
+	}
+
+	if len(cfg.CurrentContext) == 0 {
+		cfg.CurrentContext = name
+	}
+}
+
+// Resolve returns the server and credential that the context refers to.
+func (cfg *Config) Resolve(name string) (Server, Credential, error) {
+	index := cfg.contextIndex(name)
+	if index < 0 {
+		return Server{}, Credential{}, &errors.ContextNotFoundError{Name: name}
+	}
+
+	context := cfg.Contexts[index]
+
+	var server Server
+	if serverIndex := cfg.serverIndex(context.Server); serverIndex >= 0 {
+		server = cfg.Servers[serverIndex]
+	}
+
+	var credential Credential
+	if credentialIndex := cfg.credentialIndex(context.Credential); credentialIndex >= 0 {
+		credential = cfg.Credentials[credentialIndex]
+	}
+
+	return server, credential, nil
+}
+
+// Use sets the current-context to the context specified.
+func (cfg *Config) Use(name string) error {
+	if cfg.contextIndex(name) < 0 {
+		return &errors.ContextNotFoundError{Name: name}
+	}
+
+	cfg.CurrentContext = name
+
+	return nil
+}
+
+// Rename renames the context along with the server and credential it refers to, since Set stores them under the name of the context.
+// The ones referred by other contexts as well are copied under the new name instead, so that they are not changed by setting this context.
+func (cfg *Config) Rename(oldName, newName string) error {
+	index := cfg.contextIndex(oldName)
+	if index < 0 {
+		return &errors.ContextNotFoundError{Name: oldName}
+	}
+
+	if cfg.contextIndex(newName) >= 0 {
+		return &errors.CLIError{Message: fmt.Sprintf("context '%s' already exists", newName)}
+	}
+
+	context := cfg.Contexts[index]
+	shared := func(matches func(ctx Context) bool) bool {
+		return funk.Contains(cfg.Contexts, func(ctx Context) bool { return ctx.Name != oldName && matches(ctx) })
+	}
+
+	credentialIndex := cfg.credentialIndex(context.Credential)
+	if credentialIndex >= 0 && strings.HasPrefix(cfg.Credentials[credentialIndex].Encrypted, legacySealPrefix) {
+		return &errors.CLIError{Message: fmt.Sprintf("credential of context '%s' was sealed by an older version and could not be opened once renamed, "+
+			"store it again with --encrypt before renaming it", oldName)}
+	}
+
+	if serverIndex := cfg.serverIndex(context.Server); serverIndex >= 0 {
+		server := cfg.Servers[serverIndex]
+		server.Name = newName
+
+		if !shared(func(ctx Context) bool { return ctx.Server == context.Server }) {
+			cfg.Servers = append(cfg.Servers[:serverIndex], cfg.Servers[serverIndex+1:]...)
+		}
+
+		cfg.setServer(server)
+		context.Server = newName
+	}
+
+	if credentialIndex >= 0 {
+		credential := cfg.Credentials[credentialIndex]
+		credential.Name = newName
+
+		if !shared(func(ctx Context) bool { return ctx.Credential == context.Credential }) {
+			cfg.Credentials = append(cfg.Credentials[:credentialIndex], cfg.Credentials[credentialIndex+1:]...)
+		}
+
+		cfg.setCredential(credential)
+		context.Credential = newName
+	}
+
+	context.Name = newName
+	cfg.Contexts[index] = context
+
+	if cfg.CurrentContext == oldName {
+		cfg.CurrentContext = newName
+	}
+
+	return nil
+}
+
+// Delete removes the context, along with server and credential that are no longer referred by any other context.
+func (cfg *Config) Delete(name string) error {
+	index := cfg.contextIndex(name)
+	if index < 0 {
+		return &errors.ContextNotFoundError{Name: name}
+	}
+
+	context := cfg.Contexts[index]
+	cfg.Contexts = append(cfg.Contexts[:index], cfg.Contexts[index+1:]...)
+
+	if !funk.Contains(cfg.Contexts, func(ctx Context) bool { return ctx.Server == context.Server }) {
+		if serverIndex := cfg.serverIndex(context.Server); serverIndex >= 0 {
+			cfg.Servers = append(cfg.Servers[:serverIndex], cfg.Servers[serverIndex+1:]...)
+		}
+	}
+
+	if !funk.Contains(cfg.Contexts, func(ctx Context) bool { return ctx.Credential == context.Credential }) {
+		if credentialIndex := cfg.credentialIndex(context.Credential); credentialIndex >= 0 {
+			cfg.Credentials = append(cfg.Credentials[:credentialIndex], cfg.Credentials[credentialIndex+1:]...)
+		}
+	}
+
+	if cfg.CurrentContext == name {
+		cfg.CurrentContext = ""
+	}
+
+	return nil
+}
+
+func (cfg *Config) setServer(server Server) {
+	if index := cfg.serverIndex(server.Name); index >= 0 {
+		cfg.Servers[index] = server
+	} else {
+		cfg.Servers = append(cfg.Servers, server)
+	}
+}
+
+func (cfg *Config) setCredential(credential Credential) {
+	if index := cfg.credentialIndex(credential.Name); index >= 0 {
+		cfg.Credentials[index] = credential
+	} else {
+		cfg.Credentials = append(cfg.Credentials, credential)
+	}
+}
+
+func (cfg *Config) contextIndex(name string) int {
+	for index, context := range cfg.Contexts {
+		if context.Name == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func (cfg *Config) serverIndex(name string) int {
+	for index, server := range cfg.Servers {
+		if server.Name == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func (cfg *Config) credentialIndex(name string) int {
+	for index, credential := range cfg.Credentials {
+		if credential.Name == name {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,198 @@
+package contexts_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/contexts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Resolve(t *testing.T) {
+	t.Run("should be able to resolve the context that was set", func(t *testing.T) {
+		cfg := new(contexts.Config)
+		cfg.Set("central", contexts.Server{URL: "https://gocd.central.com/go"}, contexts.Credential{UserName: "admin", Password: "admin"})
+
+		server, credential, err := cfg.Resolve("central")
+		require.NoError(t, err)
+		assert.Equal(t, "https://gocd.central.com/go", server.URL)
+		assert.Equal(t, "admin", credential.UserName)
+		assert.Equal(t, "central", cfg.CurrentContext)
+	})
+
+	t.Run("should error when resolving a context that does not exist", func(t *testing.T) {
+		cfg := new(contexts.Config)
+
+		_, _, err := cfg.Resolve("central")
+		require.EqualError(t, err, "context 'central' not found")
+	})
+}
+
+func TestConfig_Current(t *testing.T) {
+	t.Run("should prefer the name passed over current-context", func(t *testing.T) {
+		cfg := &contexts.Config{CurrentContext: "central"}
+		assert.Equal(t, "edge", cfg.Current("edge"))
+		assert.Equal(t, "central", cfg.Current(""))
+	})
+
+	t.Run("should fallback to default context when nothing is set", func(t *testing.T) {
+		cfg := new(contexts.Config)
+		assert.Equal(t, contexts.DefaultContext, cfg.Current(""))
+	})
+}
+
+func TestConfig_UseRenameDelete(t *testing.T) {
+	t.Run("should be able to switch, rename and delete contexts", func(t *testing.T) {
+		cfg := new(contexts.Config)
+		cfg.Set("central", contexts.Server{URL: "https://gocd.central.com/go"}, contexts.Credential{BearerToken: "token"})
+		cfg.Set("edge", contexts.Server{URL: "https://gocd.edge.com/go"}, contexts.Credential{NoAuth: true})
+
+		require.NoError(t, cfg.Use("edge"))
+		assert.Equal(t, "edge", cfg.CurrentContext)
+		require.Error(t, cfg.Use("unknown"))
+
+		require.NoError(t, cfg.Rename("edge", "staging"))
+		assert.Equal(t, "staging", cfg.CurrentContext)
+		require.Error(t, cfg.Rename("staging", "central"))
+
+		server, _, err := cfg.Resolve("staging")
+		require.NoError(t, err)
+		assert.Equal(t, "https://gocd.edge.com/go", server.URL)
+
+		require.NoError(t, cfg.Delete("staging"))
+		assert.Empty(t, cfg.CurrentContext)
+		assert.Equal(t, []string{"central"}, cfg.Names())
+		assert.Len(t, cfg.Servers, 1)
+		assert.Len(t, cfg.Credentials, 1)
+	})
+}
+
+func TestConfig_Rename(t *testing.T) {
+	t.Run("should rename the server and credential of the context, so that setting the old name does not change them", func(t *testing.T) {
+		cfg := new(contexts.Config)
+		cfg.Set("edge", contexts.Server{URL: "https://gocd.edge.com/go"}, contexts.Credential{BearerToken: "token"})
+
+		require.NoError(t, cfg.Rename("edge", "staging"))
+		assert.Equal(t, []contexts.Context{{Name: "staging", Server: "staging", Credential: "staging"}}, cfg.Contexts)
+
+		cfg.Set("edge", contexts.Server{URL: "https://gocd.new-edge.com/go"}, contexts.Credential{NoAuth: true})
+
+		server, credential, err := cfg.Resolve("staging")
+		require.NoError(t, err)
+		assert.Equal(t, "https://gocd.edge.com/go", server.URL)
+		assert.Equal(t, "token", credential.BearerToken)
+		assert.Len(t, cfg.Servers, 2)
+		assert.Len(t, cfg.Credentials, 2)
+	})
+
+	t.Run("should copy the server and credential referred by other contexts as well", func(t *testing.T) {
+		cfg := &contexts.Config{
+			Servers:     []contexts.Server{{Name: "shared", URL: "https://gocd.shared.com/go"}},
+			Credentials: []contexts.Credential{{Name: "shared", BearerToken: "token"}},
+			Contexts: []contexts.Context{
+				{Name: "edge", Server: "shared", Credential: "shared"},
+				{Name: "central", Server: "shared", Credential: "shared"},
+			},
+		}
+
+		require.NoError(t, cfg.Rename("edge", "staging"))
+		assert.Equal(t, contexts.Context{Name: "staging", Server: "staging", Credential: "staging"}, cfg.Contexts[0])
+		assert.Equal(t, contexts.Context{Name: "central", Server: "shared", Credential: "shared"}, cfg.Contexts[1])
+		assert.Len(t, cfg.Servers, 2)
+		assert.Len(t, cfg.Credentials, 2)
+
+		server, _, err := cfg.Resolve("staging")
+		require.NoError(t, err)
+		assert.Equal(t, "https://gocd.shared.com/go", server.URL)
+	})
+
+	t.Run("should be able to open the credential sealed before renaming", func(t *testing.T) {
+		credential := contexts.Credential{UserName: "admin", Password: "admin"}
+		credential.Name = "edge"
+		require.NoError(t, credential.Seal("passphrase"))
+
+		cfg := new(contexts.Config)
+		cfg.Set("edge", contexts.Server{URL: "https://gocd.edge.com/go"}, credential)
+		require.NoError(t, cfg.Rename("edge", "staging"))
+
+		_, renamed, err := cfg.Resolve("staging")
+		require.NoError(t, err)
+		assert.Equal(t, "staging", renamed.Name)
+		require.NoError(t, renamed.Open("passphrase"))
+		assert.Equal(t, "admin", renamed.Password)
+	})
+
+	t.Run("should not rename the context whose credential was sealed along with its name", func(t *testing.T) {
+		cfg := new(contexts.Config)
+		cfg.Set("edge", contexts.Server{URL: "https://gocd.edge.com/go"}, contexts.Credential{Encrypted: "gocd-cli:v1:c2VhbGVk"})
+
+		require.EqualError(t, cfg.Rename("edge", "staging"), "credential of context 'edge' was sealed by an older version and could not be opened once renamed, "+
+			"store it again with --encrypt before renaming it")
+		assert.Equal(t, "edge", cfg.Contexts[0].Name)
+	})
+}
+
+func TestConfig_Save(t *testing.T) {
+	t.Run("should be able to save and load the contexts file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".gocd", "contexts.yaml")
+
+		cfg := new(contexts.Config)
+		cfg.Set("central", contexts.Server{URL: "https://gocd.central.com/go"}, contexts.Credential{UserName: "admin"})
+		require.NoError(t, cfg.Save(path))
+
+		loaded, err := contexts.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, cfg, loaded)
+	})
+
+	t.Run("should return empty config when the contexts file does not exist", func(t *testing.T) {
+		loaded, err := contexts.Load(filepath.Join(t.TempDir(), "contexts.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, &contexts.Config{}, loaded)
+	})
+}
+
+func TestCredential_Seal(t *testing.T) {
+	t.Run("should be able to seal and open the credential with the same passphrase", func(t *testing.T) {
+		credential := contexts.Credential{Name: "central", UserName: "admin", Password: "admin", BearerToken: "token"}
+
+		require.NoError(t, credential.Seal("passphrase"))
+		assert.True(t, credential.IsSealed())
+		assert.Empty(t, credential.Password)
+		assert.Empty(t, credential.BearerToken)
+		assert.Equal(t, "admin", credential.UserName)
+
+		require.NoError(t, credential.Open("passphrase"))
+		assert.False(t, credential.IsSealed())
+		assert.Equal(t, "admin", credential.Password)
+		assert.Equal(t, "token", credential.BearerToken)
+	})
+
+	t.Run("should fail to open the credential with a wrong passphrase", func(t *testing.T) {
+		credential := contexts.Credential{Name: "central", Password: "admin"}
+
+		require.NoError(t, credential.Seal("passphrase"))
+		require.EqualError(t, credential.Open("wrong"), "unsealing credential 'central' failed, the passphrase might be incorrect")
+	})
+
+	t.Run("should open the credential sealed by older versions, with its name authenticated", func(t *testing.T) {
+		credential := contexts.Credential{
+			Name:      "central",
+			Encrypted: "gocd-cli:v1:Kb24nzvOPxCylIcB7fOUI2WMakPZF9E9aAwKFtFpJRUqHC4prRCLmiLvtUCsgxPn8omWE/wbQVKFd3oF9/QI6w==",
+		}
+
+		require.NoError(t, credential.Open("passphrase"))
+		assert.Equal(t, "admin", credential.Password)
+	})
+
+	t.Run("should mask all the secrets of the credential", func(t *testing.T) {
+		credential := contexts.Credential{Name: "central", UserName: "admin", Password: "admin"}
+
+		masked := credential.Masked()
+		assert.Equal(t, "admin", masked.UserName)
+		assert.Equal(t, "********", masked.Password)
+		assert.Empty(t, masked.BearerToken)
+		assert.Equal(t, "admin", credential.Password)
+	})
+}
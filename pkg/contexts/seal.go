@@ -0,0 +1,168 @@
+package contexts
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// sealPrefix is the prefix of the secrets sealed along with an ID of their own, that is authenticated in place of the name of the credential.
+	sealPrefix = "gocd-cli:v2:"
+	// legacySealPrefix is the prefix of the secrets sealed by older versions, that authenticated the name of the credential.
+	// They could not be opened once the credential is renamed, and are sealed with sealPrefix the next time the credential is set.
+	legacySealPrefix = "gocd-cli:v1:"
+	idLength         = 16
+	saltLength       = 16
+	keyLength        = 32
+	kdfIterations    = 210000
+	maskedSecret     = "********"
+)
+
+type sealedSecrets struct {
+	Password    string `json:"password,omitempty"`
+	BearerToken string `json:"bearer_token,omitempty"`
+}
+
+// IsSealed returns true if the secrets of the Credential are encrypted.
+func (credential *Credential) IsSealed() bool {
+	return len(credential.Encrypted) != 0
+}
+
+// Seal encrypts the password and bearer token of the Credential with a key derived from the passphrase.
+// The secrets are moved under Encrypted, so that rest of the contexts file is still readable.
+func (credential *Credential) Seal(passphrase string) error {
+	if len(passphrase) == 0 {
+		return &errors.CLIError{Message: "passphrase cannot be empty when sealing credentials"}
+	}
+
+	secrets, err := json.Marshal(sealedSecrets{Password: credential.Password, BearerToken: credential.BearerToken})
+	if err != nil {
+		return err
+	}
+
+	// the ID is authenticated along with the secrets instead of the name of the credential, so that the credential could be renamed.
+	id := make([]byte, idLength)
+	if _, err = rand.Read(id); err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err = rand.Read(salt); err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+
+	sealed := make([]byte, 0, len(id)+len(salt)+len(nonce)+len(secrets)+aead.Overhead())
+	sealed = append(sealed, id...)
+	sealed = append(sealed, salt...)
+	sealed = append(sealed, nonce...)
+	sealed = aead.Seal(sealed, nonce, secrets, id)
+
+	credential.Encrypted = sealPrefix + base64.StdEncoding.EncodeToString(sealed)
+	credential.Password = ""
+	credential.BearerToken = ""
+
+	return nil
+}
+
+// Open decrypts the secrets sealed under Encrypted using the key derived from the passphrase.
+func (credential *Credential) Open(passphrase string) error {
+	if !credential.IsSealed() {
+		return nil
+	}
+
+	var (
+		encoded        string
+		additionalData []byte
+	)
+
+	switch {
+	case strings.HasPrefix(credential.Encrypted, sealPrefix):
+		encoded = strings.TrimPrefix(credential.Encrypted, sealPrefix)
+	case strings.HasPrefix(credential.Encrypted, legacySealPrefix):
+		encoded, additionalData = strings.TrimPrefix(credential.Encrypted, legacySealPrefix), []byte(credential.Name)
+	default:
+		return &errors.CLIError{Message: fmt.Sprintf("unsupported format of sealed credential '%s'", credential.Name)}
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	if additionalData == nil {
+		if len(sealed) < idLength {
+			return &errors.CLIError{Message: fmt.Sprintf("sealed credential '%s' is corrupted", credential.Name)}
+		}
+
+		additionalData, sealed = sealed[:idLength], sealed[idLength:]
+	}
+
+	if len(sealed) < saltLength {
+		return &errors.CLIError{Message: fmt.Sprintf("sealed credential '%s' is corrupted", credential.Name)}
+	}
+
+	aead, err := newAEAD(passphrase, sealed[:saltLength])
+	if err != nil {
+		return err
+	}
+
+	sealed = sealed[saltLength:]
+	if len(sealed) < aead.NonceSize() {
+		return &errors.CLIError{Message: fmt.Sprintf("sealed credential '%s' is corrupted", credential.Name)}
+	}
+
+	secrets, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return &errors.CLIError{Message: fmt.Sprintf("unsealing credential '%s' failed, the passphrase might be incorrect", credential.Name)}
+	}
+
+	var opened sealedSecrets
+	if err = json.Unmarshal(secrets, &opened); err != nil {
+		return err
+	}
+
+	credential.Password = opened.Password
+	credential.BearerToken = opened.BearerToken
+	credential.Encrypted = ""
+
+	return nil
+}
+
+// Masked returns a copy of the Credential with all of its secrets masked.
+func (credential Credential) Masked() Credential {
+	for _, secret := range []*string{&credential.Password, &credential.BearerToken, &credential.Encrypted} {
+		if len(*secret) != 0 {
+			*secret = maskedSecret
+		}
+	}
+
+	return credential
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, kdfIterations, keyLength, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,209 @@
+package credhelper
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+)
+
+const (
+	// DefaultTTL is the duration for which the credentials are cached when the helper does not set expires_at.
+	DefaultTTL     = 10 * time.Minute
+	filePermission = 0o600
+	dirPermission  = 0o700
+)
+
+// Request is what gets written as JSON to stdin of the credential helper.
+type Request struct {
+	ServerURL string `json:"server_url,omitempty" yaml:"server_url,omitempty"`
+	Profile   string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	UserName  string `json:"username,omitempty" yaml:"username,omitempty"`
+}
+
+// Response is what the credential helper is expected to write as JSON to its stdout.
+type Response struct {
+	UserName    string    `json:"username,omitempty" yaml:"username,omitempty"`
+	Password    string    `json:"password,omitempty" yaml:"password,omitempty"`
+	BearerToken string    `json:"bearer_token,omitempty" yaml:"bearer_token,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+}
+
+// Helper runs the external credential helper and caches the credentials returned by it.
+// The credentials are cached in memory for as long as the Helper is used, and on disk only when CacheDir is set.
+type Helper struct {
+	Command  string
+	CacheDir string
+	Timeout  time.Duration
+	cached   map[string]*Response
+}
+
+// NewHelper returns new instance of Helper, credentials would be cached on disk under cacheDir unless it is empty.
+func NewHelper(command, cacheDir string, timeout time.Duration) *Helper {
+	return &Helper{
+		Command:  command,
+		CacheDir: cacheDir,
+		Timeout:  timeout,
+		cached:   make(map[string]*Response),
+	}
+}
+
+// Get returns the credentials from cache if they have not expired, else runs the helper to fetch them.
+func (helper *Helper) Get(request Request) (*Response, error) {
+	if response := helper.fromCache(request); response != nil {
+		return response, nil
+	}
+
+	response, err := helper.Run(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.ExpiresAt.IsZero() {
+		response.ExpiresAt = time.Now().Add(DefaultTTL)
+	}
+
+	if err = helper.toCache(request, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Run runs the credential helper, by passing Request as JSON to its stdin and reading Response as JSON from its stdout.
+func (helper *Helper) Run(request Request) (*Response, error) {
+	args := strings.Fields(helper.Command)
+	if len(args) == 0 {
+		return nil, &errors.CLIError{Message: "credential helper command cannot be empty"}
+	}
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if helper.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, helper.Timeout)
+
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	command := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec
+	command.Stdin = bytes.NewReader(input)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err = command.Run(); err != nil {
+		return nil, &errors.CLIError{
+			Message: fmt.Sprintf("running credential helper '%s' errored with '%v': %s", args[0], err, strings.TrimSpace(stderr.String())),
+		}
+	}
+
+	response := new(Response)
+	if err = json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return nil, &errors.CLIError{Message: fmt.Sprintf("reading response of credential helper '%s' errored with '%v'", args[0], err)}
+	}
+
+	if len(response.BearerToken) == 0 && len(response.Password) == 0 {
+		return nil, &errors.CLIError{Message: fmt.Sprintf("credential helper '%s' returned neither bearer_token nor password", args[0])}
+	}
+
+	return response, nil
+}
+
+// Erase removes the cached credentials of the Request.
+func (helper *Helper) Erase(request Request) error {
+	delete(helper.cached, helper.cacheKey(request))
+
+	if len(helper.CacheDir) == 0 {
+		return nil
+	}
+
+	if err := os.Remove(helper.cacheFile(request)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (helper *Helper) fromCache(request Request) *Response {
+	response, ok := helper.cached[helper.cacheKey(request)]
+	if !ok {
+		response = helper.fromCacheFile(request)
+	}
+
+	if response == nil || !time.Now().Before(response.ExpiresAt) {
+		return nil
+	}
+
+	return response
+}
+
+func (helper *Helper) fromCacheFile(request Request) *Response {
+	if len(helper.CacheDir) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(helper.cacheFile(request))
+	if err != nil {
+		return nil
+	}
+
+	response := new(Response)
+	if err = json.Unmarshal(data, response); err != nil {
+		return nil
+	}
+
+	return response
+}
+
+func (helper *Helper) toCache(request Request, response *Response) error {
+	if helper.cached == nil {
+		helper.cached = make(map[string]*Response)
+	}
+
+	helper.cached[helper.cacheKey(request)] = response
+
+	if len(helper.CacheDir) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(helper.CacheDir, dirPermission); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	// the permission is set explicitly as well, since os.WriteFile retains the one of a file that already exists.
+	if err = os.WriteFile(helper.cacheFile(request), data, filePermission); err != nil {
+		return err
+	}
+
+	return os.Chmod(helper.cacheFile(request), filePermission)
+}
+
+func (helper *Helper) cacheFile(request Request) string {
+	return filepath.Join(helper.CacheDir, helper.cacheKey(request)+".json")
+}
+
+func (helper *Helper) cacheKey(request Request) string {
+	key := sha256.Sum256([]byte(strings.Join([]string{helper.Command, request.ServerURL, request.Profile, request.UserName}, "\x00")))
+
+	return hex.EncodeToString(key[:])
+}
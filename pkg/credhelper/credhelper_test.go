@@ -0,0 +1,126 @@
+package credhelper_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/credhelper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeHelper(t *testing.T, script string) string {
+	t.Helper()
+
+	helperPath := filepath.Join(t.TempDir(), "gocd-credential-helper")
+	require.NoError(t, os.WriteFile(helperPath, []byte("#!/bin/sh\n"+script), 0o700)) //nolint:gosec
+
+	return helperPath
+}
+
+func TestHelper_Get(t *testing.T) {
+	t.Run("should be able to fetch the credentials from the helper and cache them", func(t *testing.T) {
+		counter := filepath.Join(t.TempDir(), "counter")
+		helperPath := writeHelper(t, `cat > /dev/null
+echo run >> `+counter+`
+echo '{"bearer_token": "token", "expires_at": "2099-01-01T00:00:00Z"}'
+`)
+
+		cacheDir := t.TempDir()
+		helper := credhelper.NewHelper(helperPath, cacheDir, time.Minute)
+		request := credhelper.Request{ServerURL: "http://localhost:8153/go", Profile: "central"}
+
+		response, err := helper.Get(request)
+		require.NoError(t, err)
+		assert.Equal(t, "token", response.BearerToken)
+
+		cached, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+		require.NoError(t, err)
+		require.Len(t, cached, 1)
+
+		info, err := os.Stat(cached[0])
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		response, err = helper.Get(request)
+		require.NoError(t, err)
+		assert.Equal(t, "token", response.BearerToken)
+
+		runs, err := os.ReadFile(counter)
+		require.NoError(t, err)
+		assert.Equal(t, "run\n", string(runs))
+
+		require.NoError(t, helper.Erase(request))
+
+		_, err = helper.Get(request)
+		require.NoError(t, err)
+
+		runs, err = os.ReadFile(counter)
+		require.NoError(t, err)
+		assert.Equal(t, "run\nrun\n", string(runs))
+	})
+
+	t.Run("should cache the credentials only in memory, when the cache directory is not set", func(t *testing.T) {
+		counter := filepath.Join(t.TempDir(), "counter")
+		helperPath := writeHelper(t, `cat > /dev/null
+echo run >> `+counter+`
+echo '{"bearer_token": "token"}'
+`)
+
+		helper := credhelper.NewHelper(helperPath, "", time.Minute)
+		request := credhelper.Request{ServerURL: "http://localhost:8153/go", Profile: "central"}
+
+		response, err := helper.Get(request)
+		require.NoError(t, err)
+		assert.Equal(t, "token", response.BearerToken)
+
+		response, err = helper.Get(request)
+		require.NoError(t, err)
+		assert.Equal(t, "token", response.BearerToken)
+
+		runs, err := os.ReadFile(counter)
+		require.NoError(t, err)
+		assert.Equal(t, "run\n", string(runs))
+
+		_, err = credhelper.NewHelper(helperPath, "", time.Minute).Get(request)
+		require.NoError(t, err)
+
+		runs, err = os.ReadFile(counter)
+		require.NoError(t, err)
+		assert.Equal(t, "run\nrun\n", string(runs))
+	})
+
+	t.Run("should pass the request to the helper over stdin", func(t *testing.T) {
+		helperPath := writeHelper(t, `read input
+case "$input" in
+  *'"profile":"central"'*) echo '{"username": "admin", "password": "admin"}' ;;
+  *) exit 1 ;;
+esac
+`)
+
+		response, err := credhelper.NewHelper(helperPath, "", time.Minute).Get(credhelper.Request{Profile: "central"})
+		require.NoError(t, err)
+		assert.Equal(t, "admin", response.UserName)
+		assert.Equal(t, "admin", response.Password)
+		assert.False(t, response.ExpiresAt.IsZero())
+	})
+
+	t.Run("should error when the helper fails", func(t *testing.T) {
+		helperPath := writeHelper(t, `echo "vault is sealed" >&2
+exit 1
+`)
+
+		_, err := credhelper.NewHelper(helperPath, "", time.Minute).Get(credhelper.Request{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vault is sealed")
+	})
+
+	t.Run("should error when the helper returns no credentials", func(t *testing.T) {
+		helperPath := writeHelper(t, `echo '{}'`)
+
+		_, err := credhelper.NewHelper(helperPath, "", time.Minute).Get(credhelper.Request{})
+		require.Error(t, err)
+	})
+}
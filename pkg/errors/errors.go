@@ -29,3 +29,39 @@ func (e *MaterialError) Error() string {
 func (e *CLIError) Error() string {
 	return e.Message
 }
+
+func (e *ContextNotFoundError) Error() string {
+	return fmt.Sprintf("context '%s' not found", e.Name)
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *NoChangesError) Error() string {
+	return e.Message
+}
+
+func (e *UserDeclinedError) Error() string {
+	return e.Message
+}
+
+func (e *ServerUnavailableError) Error() string {
+	return e.Message
+}
+
+func (e *DriftError) Error() string {
+	return e.Message
+}
+
+func (e *ExternalCommandError) Error() string {
+	return fmt.Sprintf("external command '%s' exited with code %d", e.Name, e.Code)
+}
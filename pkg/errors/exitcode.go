@@ -0,0 +1,180 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Exit codes returned by gocd-cli, so that the scripts wrapping it can branch on the reason of failure.
+const (
+	ExitCodeOK                = 0
+	ExitCodeError             = 1
+	ExitCodeAuthFailure       = 3
+	ExitCodeNotFound          = 4
+	ExitCodeConflict          = 5
+	ExitCodeValidationFailed  = 6
+	ExitCodeNoOp              = 7
+	ExitCodeUserDeclined      = 8
+	ExitCodeServerUnavailable = 9
+	ExitCodeDrift             = 10
+)
+
+var (
+	// statusCodeRegex matches the status codes in the errors returned by GoCD sdk, ex: 'got 404 from GoCD while making GET call for ...'.
+	statusCodeRegex = regexp.MustCompile(`(?i)\b(?:got|status code:?)\s+(\d{3})\b`)
+
+	unavailableMessages = []string{"connection refused", "no such host", "i/o timeout", "connection reset by peer", "calling GoCD server"}
+
+	exitCodeReasons = map[int]string{
+		ExitCodeOK:                "ok",
+		ExitCodeError:             "error",
+		ExitCodeAuthFailure:       "auth_failure",
+		ExitCodeNotFound:          "not_found",
+		ExitCodeConflict:          "conflict",
+		ExitCodeValidationFailed:  "validation_failed",
+		ExitCodeNoOp:              "no_op",
+		ExitCodeUserDeclined:      "user_declined",
+		ExitCodeServerUnavailable: "server_unavailable",
+		ExitCodeDrift:             "drift",
+	}
+)
+
+// Envelope is the machine-readable form of an error, printed by the cli when the output format is json.
+type Envelope struct {
+	Error Detail `json:"error" yaml:"error"`
+}
+
+// Detail holds the information about the error carried by the Envelope.
+type Detail struct {
+	Reason   string `json:"reason" yaml:"reason"`
+	ExitCode int    `json:"exit_code" yaml:"exit_code"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+// ExitCode classifies the error into one of the exit codes.
+// The error types of this package are identified first, errors from GoCD sdk are then classified by the HTTP status code they carry.
+// The exit code of an external command is passed through as is.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var externalCommandError *ExternalCommandError
+	if stderrors.As(err, &externalCommandError) {
+		return externalCommandError.Code
+	}
+
+	if code, ok := exitCodeFromType(err); ok {
+		return code
+	}
+
+	var netError net.Error
+	if stderrors.As(err, &netError) {
+		return ExitCodeServerUnavailable
+	}
+
+	if code, ok := exitCodeFromStatus(StatusCode(err)); ok {
+		return code
+	}
+
+	for _, message := range unavailableMessages {
+		if strings.Contains(err.Error(), message) {
+			return ExitCodeServerUnavailable
+		}
+	}
+
+	return ExitCodeError
+}
+
+// StatusCode returns the HTTP status code carried by the error returned by GoCD sdk, it is zero when the error carries none.
+func StatusCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	match := statusCodeRegex.FindStringSubmatch(err.Error())
+	if len(match) != 2 { //nolint:mnd
+		return 0
+	}
+
+	statusCode, _ := strconv.Atoi(match[1])
+
+	return statusCode
+}
+
+// Reason returns the short name of the exit code, ex: 'not_found' for ExitCodeNotFound.
+func Reason(exitCode int) string {
+	if reason, ok := exitCodeReasons[exitCode]; ok {
+		return reason
+	}
+
+	return exitCodeReasons[ExitCodeError]
+}
+
+// NewEnvelope wraps the error into an Envelope.
+func NewEnvelope(err error) Envelope {
+	exitCode := ExitCode(err)
+
+	return Envelope{Error: Detail{Reason: Reason(exitCode), ExitCode: exitCode, Message: err.Error()}}
+}
+
+func exitCodeFromType(err error) (int, bool) {
+	var (
+		authError           *AuthError
+		notFoundError       *NotFoundError
+		contextNotFound     *ContextNotFoundError
+		conflictError       *ConflictError
+		validationError     *ValidationError
+		unknownObjectType   *UnknownObjectTypeError
+		noChangesError      *NoChangesError
+		userDeclinedError   *UserDeclinedError
+		serverUnavailable   *ServerUnavailableError
+		driftError          *DriftError
+		cipherMinKeyError   *CipherMinKeyError
+		moreArgError        MoreArgError
+		moreArgErrorPointer *MoreArgError
+	)
+
+	switch {
+	case stderrors.As(err, &authError):
+		return ExitCodeAuthFailure, true
+	case stderrors.As(err, &notFoundError), stderrors.As(err, &contextNotFound):
+		return ExitCodeNotFound, true
+	case stderrors.As(err, &conflictError):
+		return ExitCodeConflict, true
+	case stderrors.As(err, &validationError), stderrors.As(err, &unknownObjectType), stderrors.As(err, &cipherMinKeyError),
+		stderrors.As(err, &moreArgError), stderrors.As(err, &moreArgErrorPointer):
+		return ExitCodeValidationFailed, true
+	case stderrors.As(err, &noChangesError):
+		return ExitCodeNoOp, true
+	case stderrors.As(err, &userDeclinedError):
+		return ExitCodeUserDeclined, true
+	case stderrors.As(err, &serverUnavailable):
+		return ExitCodeServerUnavailable, true
+	case stderrors.As(err, &driftError):
+		return ExitCodeDrift, true
+	default:
+		return 0, false
+	}
+}
+
+func exitCodeFromStatus(statusCode int) (int, bool) {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitCodeAuthFailure, true
+	case http.StatusNotFound:
+		return ExitCodeNotFound, true
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return ExitCodeConflict, true
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ExitCodeValidationFailed, true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ExitCodeServerUnavailable, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,60 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Run("should return zero when there is no error", func(t *testing.T) {
+		assert.Equal(t, errors.ExitCodeOK, errors.ExitCode(nil))
+	})
+
+	t.Run("should identify the exit code from the error types", func(t *testing.T) {
+		assert.Equal(t, errors.ExitCodeAuthFailure, errors.ExitCode(&errors.AuthError{Message: "invalid credentials"}))
+		assert.Equal(t, errors.ExitCodeNotFound, errors.ExitCode(&errors.ContextNotFoundError{Name: "central"}))
+		assert.Equal(t, errors.ExitCodeValidationFailed, errors.ExitCode(&errors.UnknownObjectTypeError{Name: "toml"}))
+		assert.Equal(t, errors.ExitCodeNoOp, errors.ExitCode(&errors.NoChangesError{Message: "no changes"}))
+		assert.Equal(t, errors.ExitCodeUserDeclined, errors.ExitCode(&errors.UserDeclinedError{Message: "declined"}))
+		assert.Equal(t, errors.ExitCodeDrift, errors.ExitCode(&errors.DriftError{Message: "drift identified"}))
+	})
+
+	t.Run("should identify the exit code from wrapped errors", func(t *testing.T) {
+		err := fmt.Errorf("updating pipeline errored: %w", &errors.ConflictError{Message: "etag mismatch"})
+		assert.Equal(t, errors.ExitCodeConflict, errors.ExitCode(err))
+	})
+
+	t.Run("should identify the exit code from the status code carried by the error", func(t *testing.T) {
+		assert.Equal(t, errors.ExitCodeAuthFailure, errors.ExitCode(stderrors.New("got 401 from GoCD while making GET call for /api/version")))
+		assert.Equal(t, errors.ExitCodeNotFound, errors.ExitCode(stderrors.New("got 404 from GoCD while making GET call for /api/admin/pipelines/sample")))
+		assert.Equal(t, errors.ExitCodeConflict, errors.ExitCode(stderrors.New("got 412 from GoCD while making PUT call for /api/admin/pipelines/sample")))
+		assert.Equal(t, errors.ExitCodeValidationFailed, errors.ExitCode(stderrors.New("got 422 from GoCD while making POST call")))
+		assert.Equal(t, errors.ExitCodeServerUnavailable, errors.ExitCode(stderrors.New("got 503 from GoCD while making GET call")))
+	})
+
+	t.Run("should identify server unavailability from the error message", func(t *testing.T) {
+		err := stderrors.New("Get \"https://gocd.sample.com/go/api/version\": dial tcp 127.0.0.1:8153: connect: connection refused")
+		assert.Equal(t, errors.ExitCodeServerUnavailable, errors.ExitCode(err))
+	})
+
+	t.Run("should fall back to generic exit code for unknown errors", func(t *testing.T) {
+		assert.Equal(t, errors.ExitCodeError, errors.ExitCode(stderrors.New("something went wrong")))
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, 412, errors.StatusCode(stderrors.New("got 412 from GoCD while making PUT call for /api/admin/security/roles/admins")))
+	assert.Equal(t, 412, errors.StatusCode(fmt.Errorf("updating role errored with: %w", stderrors.New("status code: 412"))))
+	assert.Equal(t, 0, errors.StatusCode(stderrors.New("something went wrong")))
+	assert.Equal(t, 0, errors.StatusCode(nil))
+}
+
+func TestNewEnvelope(t *testing.T) {
+	envelope := errors.NewEnvelope(&errors.NoChangesError{Message: "no changes to the input file"})
+
+	assert.Equal(t, errors.Detail{Reason: "no_op", ExitCode: errors.ExitCodeNoOp, Message: "no changes to the input file"}, envelope.Error)
+}
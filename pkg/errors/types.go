@@ -27,3 +27,40 @@ type MaterialError struct {
 type CLIError struct {
 	Message string
 }
+
+type ContextNotFoundError struct {
+	Name string
+}
+
+type NotFoundError struct {
+	Message string
+}
+
+type ConflictError struct {
+	Message string
+}
+
+type ValidationError struct {
+	Message string
+}
+
+type NoChangesError struct {
+	Message string
+}
+
+type UserDeclinedError struct {
+	Message string
+}
+
+type ServerUnavailableError struct {
+	Message string
+}
+
+type DriftError struct {
+	Message string
+}
+
+type ExternalCommandError struct {
+	Name string
+	Code int
+}
@@ -0,0 +1,140 @@
+package extension
+
+import (
+	goerrors "errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+)
+
+// Prefix is what the name of an executable should start with, to be identified as an extension of gocd-cli.
+// ex: an executable 'gocd-cli-cleanup' would be available as 'gocd-cli cleanup'.
+const Prefix = "gocd-cli-"
+
+// Extension is an external executable, that is invoked as a subcommand of gocd-cli.
+type Extension struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// Discover finds the extensions under the directories passed, sorted by their names.
+// When more than one directory has the extension with the same name, the one found first wins, same as PATH lookup.
+func Discover(dirs ...string) []Extension {
+	extensions := make(map[string]Extension)
+
+	for _, dir := range dirs {
+		if len(dir) == 0 {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := getName(entry.Name())
+			if !ok {
+				continue
+			}
+
+			if _, found := extensions[name]; found {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			extensions[name] = Extension{Name: name, Path: path}
+		}
+	}
+
+	discovered := make([]Extension, 0, len(extensions))
+	for _, ext := range extensions {
+		discovered = append(discovered, ext)
+	}
+
+	sort.Slice(discovered, func(i, j int) bool {
+		return discovered[i].Name < discovered[j].Name
+	})
+
+	return discovered
+}
+
+// Find looks up the extension with the name passed under the directories passed, without listing them, the one found first wins.
+func Find(name string, dirs ...string) (Extension, bool) {
+	fileName := Prefix + name
+	if runtime.GOOS == "windows" {
+		fileName += ".exe"
+	}
+
+	if _, ok := getName(fileName); !ok || strings.ContainsAny(name, `/\`) {
+		return Extension{}, false
+	}
+
+	for _, dir := range dirs {
+		if len(dir) == 0 {
+			continue
+		}
+
+		if path := filepath.Join(dir, fileName); isExecutable(path) {
+			return Extension{Name: name, Path: path}, true
+		}
+	}
+
+	return Extension{}, false
+}
+
+// Run runs the extension with the args passed, the env passed is added on top of the current environment.
+// A non-zero exit of the extension is returned as errors.ExternalCommandError, carrying its exit code.
+func (ext Extension) Run(args []string, env []string) error {
+	command := exec.Command(ext.Path, args...) //nolint:gosec
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	command.Env = append(os.Environ(), env...)
+
+	if err := command.Run(); err != nil {
+		var exitError *exec.ExitError
+		if goerrors.As(err, &exitError) {
+			return &errors.ExternalCommandError{Name: ext.Name, Code: exitError.ExitCode()}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func getName(fileName string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		fileName = strings.TrimSuffix(strings.ToLower(fileName), ".exe")
+	}
+
+	name := strings.TrimPrefix(fileName, Prefix)
+	if name == fileName || len(name) == 0 || strings.ContainsAny(name, " .") {
+		return "", false
+	}
+
+	return name, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	return info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,93 @@
+package extension_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/extension"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeExecutable(t *testing.T, dir, name, content string, mode os.FileMode) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), mode))
+
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	t.Run("should discover only the executables with gocd-cli prefix", func(t *testing.T) {
+		dir := t.TempDir()
+		cleanup := writeExecutable(t, dir, "gocd-cli-cleanup", "#!/bin/sh\n", 0o755)
+		writeExecutable(t, dir, "gocd-cli-notes", "not executable", 0o644)
+		writeExecutable(t, dir, "kubectl-cleanup", "#!/bin/sh\n", 0o755)
+		writeExecutable(t, dir, "gocd-cli-", "#!/bin/sh\n", 0o755)
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "gocd-cli-dir"), 0o755))
+
+		assert.Equal(t, []extension.Extension{{Name: "cleanup", Path: cleanup}}, extension.Discover(dir))
+	})
+
+	t.Run("should prefer the extension found in the directory passed first", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+		expected := writeExecutable(t, first, "gocd-cli-report", "#!/bin/sh\n", 0o755)
+		writeExecutable(t, second, "gocd-cli-report", "#!/bin/sh\n", 0o755)
+		audit := writeExecutable(t, second, "gocd-cli-audit", "#!/bin/sh\n", 0o755)
+
+		assert.Equal(t, []extension.Extension{{Name: "audit", Path: audit}, {Name: "report", Path: expected}},
+			extension.Discover(first, "", filepath.Join(first, "missing"), second))
+	})
+}
+
+func TestFind(t *testing.T) {
+	t.Run("should find the extension in the directory passed first", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+		expected := writeExecutable(t, first, "gocd-cli-report", "#!/bin/sh\n", 0o755)
+		writeExecutable(t, second, "gocd-cli-report", "#!/bin/sh\n", 0o755)
+
+		ext, found := extension.Find("report", "", filepath.Join(first, "missing"), first, second)
+		assert.True(t, found)
+		assert.Equal(t, extension.Extension{Name: "report", Path: expected}, ext)
+	})
+
+	t.Run("should not find the files that are not executable or the names that are not valid", func(t *testing.T) {
+		dir := t.TempDir()
+		writeExecutable(t, dir, "gocd-cli-notes", "not executable", 0o644)
+		writeExecutable(t, dir, "gocd-cli-", "#!/bin/sh\n", 0o755)
+
+		for _, name := range []string{"notes", "missing", "", "../gocd-cli-notes"} {
+			_, found := extension.Find(name, dir)
+			assert.False(t, found, name)
+		}
+	})
+}
+
+func TestExtension_Run(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("should pass the args and env to the extension", func(t *testing.T) {
+		output := filepath.Join(dir, "output")
+		ext := extension.Extension{
+			Name: "echo",
+			Path: writeExecutable(t, dir, "gocd-cli-echo", "#!/bin/sh\necho \"$GOCD_SERVER_URL $*\" > "+output+"\n", 0o755),
+		}
+
+		require.NoError(t, ext.Run([]string{"sample", "--flag"}, []string{"GOCD_SERVER_URL=http://localhost:8153/go"}))
+
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:8153/go sample --flag\n", string(content))
+	})
+
+	t.Run("should return the exit code of the extension when it fails", func(t *testing.T) {
+		ext := extension.Extension{Name: "fail", Path: writeExecutable(t, dir, "gocd-cli-fail", "#!/bin/sh\nexit 4\n", 0o755)}
+
+		err := ext.Run(nil, nil)
+		require.EqualError(t, err, "external command 'fail' exited with code 4")
+		assert.Equal(t, 4, errors.ExitCode(err))
+	})
+}
@@ -0,0 +1,100 @@
+package gitrepo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	dirPermission = 0o755
+
+	// DefaultUserName and DefaultUserEmail are the identity the commits are made with, when git has no identity configured.
+	DefaultUserName  = "gocd-cli"
+	DefaultUserEmail = "gocd-cli@localhost"
+)
+
+// Repo is a git working tree, operated by running the git binary found in PATH.
+type Repo struct {
+	dir string
+}
+
+// Init returns the Repo under dir, the directory is created and initialised as a git repository when it is not one already.
+func Init(dir string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required to track the changes under '%s': %w", dir, err)
+	}
+
+	if err := os.MkdirAll(dir, dirPermission); err != nil {
+		return nil, err
+	}
+
+	repo := &Repo{dir: dir}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return repo, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if _, err := repo.run("init", "--quiet"); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Dir returns the directory of the working tree.
+func (repo *Repo) Dir() string {
+	return repo.dir
+}
+
+// Commit stages all the changes under the working tree and commits them with the message passed.
+// It returns false when there was nothing to commit.
+func (repo *Repo) Commit(message string) (bool, error) {
+	if _, err := repo.run("add", "--all"); err != nil {
+		return false, err
+	}
+
+	status, err := repo.run("status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+
+	if len(strings.TrimSpace(status)) == 0 {
+		return false, nil
+	}
+
+	args := []string{"commit", "--quiet", "--file", "-"}
+	if email, err := repo.run("config", "user.email"); err != nil || len(strings.TrimSpace(email)) == 0 {
+		args = append([]string{"-c", "user.name=" + DefaultUserName, "-c", "user.email=" + DefaultUserEmail}, args...)
+	}
+
+	if _, err = repo.runWithInput(message, args...); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (repo *Repo) run(args ...string) (string, error) {
+	return repo.runWithInput("", args...)
+}
+
+func (repo *Repo) runWithInput(input string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	command := exec.Command("git", append([]string{"-C", repo.dir}, args...)...)
+	command.Stdin = strings.NewReader(input)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("running 'git %s' errored with: %w, %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
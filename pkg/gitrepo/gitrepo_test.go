@@ -0,0 +1,67 @@
+package gitrepo_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/gitrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_Commit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := filepath.Join(t.TempDir(), "gocd-history")
+
+	repo, err := gitrepo.Init(dir)
+	require.NoError(t, err)
+	assert.DirExists(t, filepath.Join(dir, ".git"))
+
+	gitLog := func(t *testing.T, format string) string {
+		t.Helper()
+
+		out, err := exec.Command("git", "-C", dir, "log", "--format="+format).Output()
+		require.NoError(t, err)
+
+		return strings.TrimSpace(string(out))
+	}
+
+	t.Run("should commit all the changes under the working tree", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "roles"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "roles", "admins.yaml"), []byte("kind: Role\nname: admins\n"), 0o600))
+
+		committed, err := repo.Commit("Add Role 'admins'\n\n- added Role 'admins'")
+		require.NoError(t, err)
+		assert.True(t, committed)
+
+		assert.Equal(t, "Add Role 'admins'\n\n- added Role 'admins'", gitLog(t, "%B"))
+		assert.Equal(t, gitrepo.DefaultUserName+" <"+gitrepo.DefaultUserEmail+">", gitLog(t, "%an <%ae>"))
+	})
+
+	t.Run("should not commit when nothing changed", func(t *testing.T) {
+		committed, err := repo.Commit("nothing")
+		require.NoError(t, err)
+		assert.False(t, committed)
+	})
+
+	t.Run("should commit the files removed", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "roles", "admins.yaml")))
+
+		reopened, err := gitrepo.Init(dir)
+		require.NoError(t, err)
+
+		committed, err := reopened.Commit("Remove Role 'admins'")
+		require.NoError(t, err)
+		assert.True(t, committed)
+		assert.Equal(t, "Remove Role 'admins'\nAdd Role 'admins'", gitLog(t, "%s"))
+	})
+}
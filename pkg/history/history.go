@@ -0,0 +1,379 @@
+package history
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+)
+
+const (
+	filePermission = 0o600
+	dirPermission  = 0o700
+	fileExtension  = ".json"
+	idSuffixLength = 3
+	idTimeFormat   = "20060102T150405"
+)
+
+// Entry is a resource as it was on GoCD server before a call modified or deleted it, along with the details of the call.
+type Entry struct {
+	ID       string          `json:"id,omitempty" yaml:"id,omitempty"`
+	Time     time.Time       `json:"time,omitempty" yaml:"time,omitempty"`
+	User     string          `json:"user,omitempty" yaml:"user,omitempty"`
+	GoCDUser string          `json:"gocd_user,omitempty" yaml:"gocd_user,omitempty"`
+	Profile  string          `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Server   string          `json:"server,omitempty" yaml:"server,omitempty"`
+	Command  string          `json:"command,omitempty" yaml:"command,omitempty"`
+	Kind     string          `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name     string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Method   string          `json:"method,omitempty" yaml:"method,omitempty"`
+	URL      string          `json:"url,omitempty" yaml:"url,omitempty"`
+	Accept   string          `json:"accept,omitempty" yaml:"accept,omitempty"`
+	ETag     string          `json:"etag,omitempty" yaml:"etag,omitempty"`
+	Object   json.RawMessage `json:"object,omitempty" yaml:"-"`
+}
+
+// Resolver returns the kind and the name of the resource a call is made to, ok is false when the call is not made to a single resource.
+// ex: the calls made to the collections, like the bulk delete of users, are not saved to the history.
+type Resolver func(req *http.Request) (kind, name string, ok bool)
+
+// Journal saves the entries as JSON files under a directory, one file per entry.
+type Journal struct {
+	dir string
+}
+
+// New returns new instance of Journal, that saves the entries under dir.
+func New(dir string) *Journal {
+	return &Journal{dir: dir}
+}
+
+// Dir returns the directory under which the entries are saved.
+func (journal *Journal) Dir() string {
+	return journal.dir
+}
+
+// Save saves the entry, the ID and the time are set if the entry does not have them.
+// The files are readable only by the user since the resources could hold secrets.
+func (journal *Journal) Save(entry *Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	if len(entry.ID) == 0 {
+		suffix := make([]byte, idSuffixLength)
+		if _, err := rand.Read(suffix); err != nil {
+			return err
+		}
+
+		entry.ID = fmt.Sprintf("%s-%s", entry.Time.Format(idTimeFormat), hex.EncodeToString(suffix))
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(journal.dir, dirPermission); err != nil {
+		return err
+	}
+
+	return os.WriteFile(journal.path(entry.ID), data, filePermission)
+}
+
+// Delete removes the entry, removing an entry that does not exist is not an error.
+func (journal *Journal) Delete(id string) error {
+	if err := os.Remove(journal.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Prune removes the entries beyond the latest maxEntries, along with the ones saved before maxAge, it returns the IDs of the entries removed.
+// Either of the limits is not applied when it is not greater than zero. The entries are aged by the time in their IDs, without reading them.
+func (journal *Journal) Prune(maxEntries int, maxAge time.Duration) ([]string, error) {
+	files, err := os.ReadDir(journal.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(files))
+
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == fileExtension {
+			ids = append(ids, strings.TrimSuffix(file.Name(), fileExtension))
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	removed := make([]string, 0)
+
+	for index, id := range ids {
+		if !journal.isExpired(index, id, maxEntries, maxAge) {
+			continue
+		}
+
+		if err = journal.Delete(id); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, id)
+	}
+
+	return removed, nil
+}
+
+// List returns all the entries saved, the latest first.
+func (journal *Journal) List() ([]Entry, error) {
+	files, err := os.ReadDir(journal.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Entry{}, nil
+		}
+
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(files))
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != fileExtension {
+			continue
+		}
+
+		entry, err := journal.read(strings.TrimSuffix(file.Name(), fileExtension))
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ID > entries[j].ID
+	})
+
+	return entries, nil
+}
+
+// Get returns the entry identified by the ID passed, a prefix of the ID is accepted as long as it identifies a single entry.
+func (journal *Journal) Get(id string) (*Entry, error) {
+	if entry, err := journal.read(id); err == nil {
+		return entry, nil
+	}
+
+	entries, err := journal.List()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]Entry, 0)
+
+	for _, entry := range entries {
+		if len(id) != 0 && strings.HasPrefix(entry.ID, id) {
+			matched = append(matched, entry)
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, &errors.NotFoundError{Message: fmt.Sprintf("history entry '%s' not found under '%s'", id, journal.dir)}
+	case 1:
+		return &matched[0], nil
+	default:
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("history entry '%s' is ambiguous, it matches %d entries", id, len(matched))}
+	}
+}
+
+// Middleware returns the transport.Middleware that saves the resource as it is on GoCD server, before every call that could modify or delete it.
+// Only the calls made to a single resource identified by resolve are saved, the details returned by describe are saved along with the resource.
+// The resource is taken from the response of the GET call made to it earlier, when its ETag matches the one the call is made with,
+// and is fetched by a GET call to the URL of the call otherwise. Entries of the calls that did not succeed are discarded, since the resource was not modified by them.
+func (journal *Journal) Middleware(describe func() Entry, resolve Resolver) transport.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		var (
+			mutex   sync.Mutex
+			fetched = make(map[string]*Entry)
+		)
+
+		return transport.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			kind, name, ok := resolve(req)
+			if !ok {
+				return next.RoundTrip(req)
+			}
+
+			resourceURL := req.URL.String()
+
+			switch req.Method {
+			case http.MethodGet:
+				resp, err := next.RoundTrip(req)
+				if err != nil || resp.StatusCode != http.StatusOK {
+					return resp, err
+				}
+
+				entry, err := readEntry(req, resp)
+				if err != nil {
+					return nil, err
+				}
+
+				if entry == nil {
+					return resp, nil
+				}
+
+				mutex.Lock()
+				fetched[resourceURL] = entry
+				mutex.Unlock()
+
+				return resp, nil
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				return next.RoundTrip(req)
+			}
+
+			mutex.Lock()
+			entry, ok := fetched[resourceURL]
+			mutex.Unlock()
+
+			if ifMatch := req.Header.Get("If-Match"); !ok || (len(ifMatch) != 0 && ifMatch != entry.ETag) {
+				var err error
+				if entry, err = journal.snapshot(next, req); err != nil {
+					return nil, fmt.Errorf("saving the resource to history before '%s %s' errored with: %w", req.Method, req.URL.Path, err)
+				}
+			}
+
+			if entry == nil {
+				return next.RoundTrip(req)
+			}
+
+			details := describe()
+			details.Method, details.URL, details.Accept, details.ETag, details.Object = req.Method, resourceURL, req.Header.Get("Accept"), entry.ETag, entry.Object
+			details.Kind, details.Name = kind, name
+
+			if err := journal.Save(&details); err != nil {
+				return nil, fmt.Errorf("saving the resource to history before '%s %s' errored with: %w", req.Method, req.URL.Path, err)
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil || resp.StatusCode >= http.StatusBadRequest {
+				// the call failed, the resource was not modified and there is nothing to roll back to.
+				_ = journal.Delete(details.ID)
+
+				return resp, err
+			}
+
+			mutex.Lock()
+			delete(fetched, resourceURL)
+			mutex.Unlock()
+
+			return resp, nil
+		})
+	}
+}
+
+// snapshot fetches the resource the call is made to, it returns nil if the resource could not be fetched as JSON.
+func (journal *Journal) snapshot(next http.RoundTripper, req *http.Request) (*Entry, error) {
+	getReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, req.URL.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	getReq.Header = req.Header.Clone()
+	for _, header := range []string{"Content-Type", "Content-Length", "If-Match", "X-GoCD-Confirm"} {
+		getReq.Header.Del(header)
+	}
+
+	resp, err := next.RoundTrip(getReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil //nolint:nilnil
+	}
+
+	return readEntry(getReq, resp)
+}
+
+// readEntry returns the resource held by the response of the GET call, the body of the response is left readable by the caller.
+// It returns nil if the resource is not JSON.
+func readEntry(req *http.Request, resp *http.Response) (*Entry, error) {
+	object, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(object))
+
+	if !json.Valid(object) {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &Entry{
+		URL:    req.URL.String(),
+		Accept: req.Header.Get("Accept"),
+		ETag:   resp.Header.Get("ETag"),
+		Object: object,
+	}, nil
+}
+
+// isExpired returns true if the entry at the index passed, among the entries sorted with the latest first, is beyond the limits of Prune.
+func (journal *Journal) isExpired(index int, id string, maxEntries int, maxAge time.Duration) bool {
+	if maxEntries > 0 && index >= maxEntries {
+		return true
+	}
+
+	if maxAge <= 0 {
+		return false
+	}
+
+	savedTime, _, _ := strings.Cut(id, "-")
+
+	savedAt, err := time.Parse(idTimeFormat, savedTime)
+	if err != nil {
+		return false
+	}
+
+	return savedAt.Before(time.Now().UTC().Add(-maxAge))
+}
+
+func (journal *Journal) read(id string) (*Entry, error) {
+	if len(id) == 0 || strings.ContainsAny(id, `/\`) {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("invalid history entry '%s'", id)}
+	}
+
+	data, err := os.ReadFile(journal.path(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry Entry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("reading history entry '%s' errored with: %v", id, err)}
+	}
+
+	return &entry, nil
+}
+
+func (journal *Journal) path(id string) string {
+	return filepath.Join(journal.dir, id+fileExtension)
+}
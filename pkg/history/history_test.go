@@ -0,0 +1,262 @@
+package history_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/history"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	journal := history.New(t.TempDir())
+
+	first := &history.Entry{ID: "20261017T101500-aaaaaa", Command: "gocd-cli roles update"}
+	second := &history.Entry{ID: "20261017T101600-bbbbbb", Command: "gocd-cli pipeline update"}
+	third := &history.Entry{ID: "20261017T101600-bbcccc", Command: "gocd-cli environment delete"}
+
+	for _, entry := range []*history.Entry{first, second, third} {
+		require.NoError(t, journal.Save(entry))
+	}
+
+	t.Run("should list the entries with the latest first", func(t *testing.T) {
+		entries, err := journal.List()
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+
+		assert.Equal(t, []string{third.ID, second.ID, first.ID}, ids)
+	})
+
+	t.Run("should get the entry by its id or a unique prefix of it", func(t *testing.T) {
+		entry, err := journal.Get(first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "gocd-cli roles update", entry.Command)
+
+		entry, err = journal.Get("20261017T101600-bbc")
+		require.NoError(t, err)
+		assert.Equal(t, third.ID, entry.ID)
+	})
+
+	t.Run("should error when the prefix is ambiguous or matches nothing", func(t *testing.T) {
+		_, err := journal.Get("20261017T101600")
+		var validationError *errors.ValidationError
+		assert.ErrorAs(t, err, &validationError)
+
+		_, err = journal.Get("20251017")
+		var notFoundError *errors.NotFoundError
+		assert.ErrorAs(t, err, &notFoundError)
+	})
+
+	t.Run("should set the id and the time of the entries saved without them", func(t *testing.T) {
+		entry := &history.Entry{Command: "gocd-cli agents update"}
+		require.NoError(t, journal.Save(entry))
+
+		assert.False(t, entry.Time.IsZero())
+		assert.True(t, strings.HasPrefix(entry.ID, entry.Time.Format("20060102T150405")+"-"))
+
+		require.NoError(t, journal.Delete(entry.ID))
+		require.NoError(t, journal.Delete(entry.ID))
+	})
+
+	t.Run("should prune the entries beyond the latest ones kept, along with the ones older than the age kept", func(t *testing.T) {
+		pruned := history.New(t.TempDir())
+
+		oldest := &history.Entry{ID: "20200101T101500-aaaaaa"}
+		older := &history.Entry{ID: "20200102T101500-bbbbbb"}
+		old := &history.Entry{ID: "20200103T101500-cccccc"}
+		recent := &history.Entry{Command: "gocd-cli roles update"}
+
+		for _, entry := range []*history.Entry{oldest, older, old, recent} {
+			require.NoError(t, pruned.Save(entry))
+		}
+
+		removed, err := pruned.Prune(3, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{oldest.ID}, removed)
+
+		removed, err = pruned.Prune(0, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []string{old.ID, older.ID}, removed)
+
+		entries, err := pruned.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, recent.ID, entries[0].ID)
+
+		removed, err = history.New(t.TempDir()+"/history").Prune(1, time.Hour)
+		require.NoError(t, err)
+		assert.Empty(t, removed)
+	})
+
+	t.Run("should list no entries when nothing was saved", func(t *testing.T) {
+		entries, err := history.New(t.TempDir() + "/history").List()
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestJournal_Middleware(t *testing.T) {
+	roles := map[string]string{"admins": `{"name": "admins", "type": "gocd"}`}
+	eTags := map[string]string{"admins": `"etag-admins"`}
+	var gets int
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		name := strings.TrimPrefix(req.URL.Path, "/go/api/admin/security/roles/")
+
+		switch req.Method {
+		case http.MethodGet:
+			gets++
+
+			role, ok := roles[name]
+			if !ok {
+				http.NotFound(writer, req)
+
+				return
+			}
+
+			writer.Header().Set("ETag", eTags[name])
+			_, _ = io.WriteString(writer, role)
+		case http.MethodPut:
+			if req.Header.Get("If-Match") != eTags[name] {
+				writer.WriteHeader(http.StatusPreconditionFailed)
+
+				return
+			}
+
+			body, _ := io.ReadAll(req.Body)
+			roles[name] = string(body)
+			_, _ = writer.Write(body)
+		case http.MethodDelete:
+			delete(roles, name)
+		}
+	}))
+	defer server.Close()
+
+	journal := history.New(t.TempDir())
+
+	base, err := transport.NewTransport(transport.Config{})
+	require.NoError(t, err)
+
+	roundTripper := transport.Chain(base, journal.Middleware(func() history.Entry {
+		return history.Entry{Profile: "production", Server: server.URL + "/go", Command: "gocd-cli role update", Time: time.Now()}
+	}, func(req *http.Request) (string, string, bool) {
+		name := strings.TrimPrefix(req.URL.Path, "/go/api/admin/security/roles/")
+		if name == req.URL.Path || len(name) == 0 {
+			return "", "", false
+		}
+
+		return "Role", name, true
+	}))
+
+	doCall := func(t *testing.T, method, name, ifMatch, body string) int {
+		t.Helper()
+
+		req, err := http.NewRequest(method, server.URL+"/go/api/admin/security/roles/"+name, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/vnd.go.cd.v3+json")
+		req.Header.Set("If-Match", ifMatch)
+
+		resp, err := roundTripper.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	t.Run("should save the resource before it is updated", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodPut, "admins", `"etag-admins"`, `{"name": "admins", "type": "plugin"}`))
+
+		entries, err := journal.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		assert.Equal(t, "production", entries[0].Profile)
+		assert.Equal(t, "gocd-cli role update", entries[0].Command)
+		assert.Equal(t, "Role", entries[0].Kind)
+		assert.Equal(t, "admins", entries[0].Name)
+		assert.Equal(t, http.MethodPut, entries[0].Method)
+		assert.Equal(t, server.URL+"/go/api/admin/security/roles/admins", entries[0].URL)
+		assert.Equal(t, "application/vnd.go.cd.v3+json", entries[0].Accept)
+		assert.Equal(t, `"etag-admins"`, entries[0].ETag)
+		assert.JSONEq(t, `{"name": "admins", "type": "gocd"}`, string(entries[0].Object))
+
+		require.NoError(t, journal.Delete(entries[0].ID))
+	})
+
+	t.Run("should discard the entry when the call fails", func(t *testing.T) {
+		assert.Equal(t, http.StatusPreconditionFailed, doCall(t, http.MethodPut, "admins", `"stale"`, `{"name": "admins"}`))
+
+		entries, err := journal.List()
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should not save the calls that do not modify a resource that exists", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodGet, "admins", "", ""))
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodDelete, "viewers", "", ""))
+
+		entries, err := journal.List()
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should not save the calls that are not made to a single resource", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodDelete, "", "", `{"roles": ["admins"]}`))
+
+		entries, err := journal.List()
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should save the resource fetched earlier without fetching it again", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodGet, "admins", "", ""))
+		gets = 0
+
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodPut, "admins", `"etag-admins"`, `{"name": "admins", "type": "plugin"}`))
+		assert.Equal(t, 0, gets)
+
+		entries, err := journal.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.JSONEq(t, `{"name": "admins", "type": "plugin"}`, string(entries[0].Object))
+
+		require.NoError(t, journal.Delete(entries[0].ID))
+	})
+
+	t.Run("should fetch the resource again when it was modified since", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodGet, "admins", "", ""))
+		roles["admins"], eTags["admins"] = `{"name": "admins", "type": "modified"}`, `"etag-modified"`
+		gets = 0
+
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodPut, "admins", `"etag-modified"`, `{"name": "admins", "type": "plugin"}`))
+		assert.Equal(t, 1, gets)
+
+		entries, err := journal.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.JSONEq(t, `{"name": "admins", "type": "modified"}`, string(entries[0].Object))
+
+		require.NoError(t, journal.Delete(entries[0].ID))
+	})
+
+	t.Run("should save the resource before it is deleted", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodDelete, "admins", "", ""))
+
+		entries, err := journal.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, http.MethodDelete, entries[0].Method)
+		assert.JSONEq(t, `{"name": "admins", "type": "plugin"}`, string(entries[0].Object))
+	})
+}
@@ -0,0 +1,391 @@
+package manifest
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ghodssYAML "github.com/ghodss/yaml"
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of the resources that could be declared in the manifests.
+const (
+	KindSiteURL             = "SiteURL"
+	KindMailServer          = "MailServer"
+	KindJobTimeout          = "JobTimeout"
+	KindBackupConfig        = "BackupConfig"
+	KindPluginSettings      = "PluginSettings"
+	KindAuthConfig          = "AuthConfig"
+	KindRole                = "Role"
+	KindClusterProfile      = "ClusterProfile"
+	KindElasticAgentProfile = "ElasticAgentProfile"
+	KindArtifactStore       = "ArtifactStore"
+	KindConfigRepo          = "ConfigRepo"
+	KindPipelineGroup       = "PipelineGroup"
+	KindPipeline            = "Pipeline"
+	KindEnvironment         = "Environment"
+
+	// KindKey is the field the kind is set in, on top of the resource in the manifests.
+	KindKey         = "kind"
+	fileExtension   = ".yaml"
+	serverConfigDir = "server"
+)
+
+// Kinds of the objects accepted as input by the commands, that are not declared in the manifests.
+const (
+	KindAgent           = "Agent"
+	KindSchedule        = "Schedule"
+	KindUser            = "User"
+	KindUsersBulkDelete = "UsersBulkDelete"
+)
+
+// kindOrder is the order in which the resources are applied, a resource comes after the ones it depends on.
+// ex: cluster profile before the elastic agent profiles using it, pipeline group before its pipelines and pipelines before the environments.
+var kindOrder = []string{
+	KindSiteURL,
+	KindMailServer,
+	KindJobTimeout,
+	KindBackupConfig,
+	KindPluginSettings,
+	KindAuthConfig,
+	KindRole,
+	KindClusterProfile,
+	KindElasticAgentProfile,
+	KindArtifactStore,
+	KindConfigRepo,
+	KindPipelineGroup,
+	KindPipeline,
+	KindEnvironment,
+}
+
+// kindDirs are the directories under which the resources of each kind are saved by Path.
+var kindDirs = map[string]string{
+	KindSiteURL:             serverConfigDir,
+	KindMailServer:          serverConfigDir,
+	KindJobTimeout:          serverConfigDir,
+	KindBackupConfig:        serverConfigDir,
+	KindPluginSettings:      "plugin-settings",
+	KindAuthConfig:          "auth-configs",
+	KindRole:                "roles",
+	KindClusterProfile:      "cluster-profiles",
+	KindElasticAgentProfile: "elastic-agent-profiles",
+	KindArtifactStore:       "artifact-stores",
+	KindConfigRepo:          "config-repos",
+	KindPipelineGroup:       "pipeline-groups",
+	KindPipeline:            "pipelines",
+	KindEnvironment:         "environments",
+}
+
+// readOnlyFields are the fields set by GoCD server, that are dropped by Marshal. An etag saved along with the resource would fail the updates once it is stale.
+var readOnlyFields = []string{"etag", "_links"}
+
+// fileNameReplacer replaces the characters in the names of the resources, that are not safe in file names.
+var fileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_")
+
+// secretFields are the fields holding the values encrypted by GoCD server, with the cipher key of the server.
+var secretFields = []string{"encrypted_value", "encrypted_password"}
+
+// manifestExtensions are the extensions of the files read, when a directory is passed.
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// Document is a resource declared in a manifest, the object holds the resource without its kind.
+type Document struct {
+	Kind   string          `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Source string          `json:"source,omitempty" yaml:"source,omitempty"`
+	Object json.RawMessage `json:"object,omitempty" yaml:"object,omitempty"`
+	// node is the document as it was read from the manifest, so that the fields not matching the schema are reported with their line and column.
+	node *yaml.Node
+}
+
+// Kinds returns the kinds of resources supported, in the order they are applied.
+func Kinds() []string {
+	return append([]string{}, kindOrder...)
+}
+
+// Read reads the documents from the files passed, directories are walked for the YAML and JSON files under them.
+// A file could hold more than one document, separated by '---'. The documents are returned in the order they should be applied.
+func Read(paths ...string) ([]Document, error) {
+	files, err := getFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]Document, 0)
+
+	for _, file := range files {
+		fileDocuments, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, fileDocuments...)
+	}
+
+	sort.SliceStable(documents, func(i, j int) bool {
+		return kindIndex(documents[i].Kind) < kindIndex(documents[j].Kind)
+	})
+
+	return documents, nil
+}
+
+// Decode decodes the object of the document into the value passed, after validating it against the schema of the value.
+// The fields unknown to the value fail the decoding, rather than being dropped silently.
+func (document Document) Decode(value interface{}) error {
+	objectSchema := schema.Generate(document.Kind, value)
+
+	var err error
+	if document.node != nil {
+		err = objectSchema.ValidateNode(document.node, KindKey)
+	} else {
+		err = objectSchema.Validate(document.Object)
+	}
+
+	if err != nil {
+		return &errors.ValidationError{Message: fmt.Sprintf("decoding %s from %s errored with: %v", document.Kind, document.Source, err)}
+	}
+
+	if err = json.Unmarshal(document.Object, value); err != nil {
+		return &errors.ValidationError{Message: fmt.Sprintf("decoding %s from %s errored with: %v", document.Kind, document.Source, err)}
+	}
+
+	return nil
+}
+
+// Marshal returns the object as a manifest document in YAML with its kind on top, the document is readable by Read as well as by
+// the create/update commands accepting --from-file. The read only fields and the fields passed as omit are dropped.
+func Marshal(kind string, object interface{}, omit ...string) ([]byte, error) {
+	objectJSON, err := marshalFields(object, omit)
+	if err != nil {
+		return nil, err
+	}
+
+	objectYAML, err := ghodssYAML.JSONToYAML(objectJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(fmt.Sprintf("%s: %s\n", KindKey, kind)), objectYAML...), nil
+}
+
+// NewDocument returns the object as a document of the kind passed, as if it was read from a manifest saved by Marshal.
+func NewDocument(kind, source string, object interface{}, omit ...string) (Document, error) {
+	objectJSON, err := marshalFields(object, omit)
+	if err != nil {
+		return Document{}, err
+	}
+
+	return Document{Kind: kind, Source: source, Object: objectJSON}, nil
+}
+
+// ReplaceSecrets replaces the encrypted values held by the object of the document, with the ones returned by replace.
+// ex: the values could be re-encrypted with the cipher key of another GoCD server.
+func (document *Document) ReplaceSecrets(replace func(value string) (string, error)) error {
+	var object interface{}
+	if err := json.Unmarshal(document.Object, &object); err != nil {
+		return err
+	}
+
+	if err := replaceSecrets(object, replace); err != nil {
+		return fmt.Errorf("replacing encrypted values of %s from %s errored with: %w", document.Kind, document.Source, err)
+	}
+
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	document.Object = objectJSON
+
+	return nil
+}
+
+// Path returns the path relative to the directory of manifests, under which the resource is saved. ex: environments/production.yaml.
+func Path(kind, name string) string {
+	return filepath.Join(Dir(kind), fileNameReplacer.Replace(name)+fileExtension)
+}
+
+// Dir returns the directory relative to the directory of manifests, under which the resources of the kind are saved.
+func Dir(kind string) string {
+	if dir, ok := kindDirs[kind]; ok {
+		return dir
+	}
+
+	return strings.ToLower(kind)
+}
+
+func marshalFields(object interface{}, omit []string) ([]byte, error) {
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err = json.Unmarshal(objectJSON, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, field := range append(append([]string{KindKey}, readOnlyFields...), omit...) {
+		delete(fields, field)
+	}
+
+	return json.Marshal(fields)
+}
+
+func replaceSecrets(object interface{}, replace func(value string) (string, error)) error {
+	switch value := object.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if secret, ok := field.(string); ok && isSecretField(key) && len(secret) != 0 {
+				replaced, err := replace(secret)
+				if err != nil {
+					return err
+				}
+
+				value[key] = replaced
+
+				continue
+			}
+
+			if err := replaceSecrets(field, replace); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := replaceSecrets(item, replace); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func isSecretField(field string) bool {
+	for _, secretField := range secretFields {
+		if field == secretField {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getFiles(paths []string) ([]string, error) {
+	files := make([]string, 0)
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(filePath string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !entry.IsDir() && isManifest(filePath) {
+				files = append(files, filePath)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func readFile(file string) ([]Document, error) {
+	data, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer data.Close()
+
+	documents := make([]Document, 0)
+	decoder := yaml.NewDecoder(data)
+
+	for index := 1; ; index++ {
+		var (
+			node   yaml.Node
+			object map[string]interface{}
+		)
+
+		if err = decoder.Decode(&node); err != nil {
+			if goerrors.Is(err, io.EOF) {
+				return documents, nil
+			}
+
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("reading document %d of '%s' errored with: %v", index, file, err)}
+		}
+
+		if err = node.Decode(&object); err != nil {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("reading document %d of '%s' errored with: %v", index, file, err)}
+		}
+
+		if len(object) == 0 {
+			continue
+		}
+
+		source := fmt.Sprintf("document %d of '%s'", index, file)
+
+		kind, _ := object[KindKey].(string)
+		if len(kind) == 0 {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("%s does not have the kind set", source)}
+		}
+
+		if kindIndex(kind) == len(kindOrder) {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("kind '%s' of %s is not supported, it should be one of %s",
+				kind, source, strings.Join(kindOrder, "|"))}
+		}
+
+		delete(object, KindKey)
+
+		objectJSON, err := json.Marshal(object)
+		if err != nil {
+			return nil, &errors.ValidationError{Message: fmt.Sprintf("reading %s errored with: %v", source, err)}
+		}
+
+		documents = append(documents, Document{Kind: kind, Source: source, Object: objectJSON, node: &node})
+	}
+}
+
+func kindIndex(kind string) int {
+	for index, orderedKind := range kindOrder {
+		if orderedKind == kind {
+			return index
+		}
+	}
+
+	return len(kindOrder)
+}
+
+func isManifest(file string) bool {
+	extension := strings.ToLower(filepath.Ext(file))
+	for _, manifestExtension := range manifestExtensions {
+		if extension == manifestExtension {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,180 @@
+package manifest_test
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	writeFile := func(t *testing.T, dir, name, data string) string {
+		t.Helper()
+
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+		return path
+	}
+
+	t.Run("should read the documents from the directory in the order they should be applied", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "pipelines.yaml", `kind: Environment
+name: production
+pipelines:
+  - name: sample
+---
+kind: Pipeline
+name: sample
+group: movies
+---
+`)
+		writeFile(t, dir, "profiles/elastic.json", `{"kind": "ElasticAgentProfile", "id": "docker"}`)
+		writeFile(t, dir, "profiles/cluster.yml", `kind: ClusterProfile
+id: kubernetes
+`)
+		writeFile(t, dir, "README.md", "not a manifest")
+
+		documents, err := manifest.Read(dir)
+		require.NoError(t, err)
+		require.Len(t, documents, 4)
+
+		kinds := make([]string, 0, len(documents))
+		for _, document := range documents {
+			kinds = append(kinds, document.Kind)
+		}
+
+		assert.Equal(t, []string{manifest.KindClusterProfile, manifest.KindElasticAgentProfile, manifest.KindPipeline, manifest.KindEnvironment}, kinds)
+		assert.JSONEq(t, `{"name": "sample", "group": "movies"}`, string(documents[2].Object))
+		assert.Equal(t, "document 2 of '"+filepath.Join(dir, "pipelines.yaml")+"'", documents[2].Source)
+
+		var environment struct {
+			Name      string `json:"name"`
+			Pipelines []struct {
+				Name string `json:"name"`
+			} `json:"pipelines"`
+		}
+		require.NoError(t, documents[3].Decode(&environment))
+		assert.Equal(t, "production", environment.Name)
+		assert.Equal(t, "sample", environment.Pipelines[0].Name)
+	})
+
+	t.Run("should fail to decode the documents with the fields not declared, along with their line and column in the file", func(t *testing.T) {
+		path := writeFile(t, t.TempDir(), "roles.yaml", `kind: Role
+name: admins
+---
+kind: Role
+name: viewers
+polices: []
+`)
+
+		documents, err := manifest.Read(path)
+		require.NoError(t, err)
+		require.Len(t, documents, 2)
+
+		var role struct {
+			Name     string   `json:"name,omitempty"`
+			Policies []string `json:"policies,omitempty"`
+		}
+		require.NoError(t, documents[0].Decode(&role))
+		assert.EqualError(t, documents[1].Decode(&role), "decoding Role from document 2 of '"+path+"' errored with: object does not match the schema of Role:\n"+
+			"line 6, column 1: unknown field 'polices', did you mean 'policies'?")
+	})
+
+	t.Run("should fail when the kind is not set", func(t *testing.T) {
+		path := writeFile(t, t.TempDir(), "role.yaml", "name: admins\n")
+
+		_, err := manifest.Read(path)
+		assert.EqualError(t, err, "document 1 of '"+path+"' does not have the kind set")
+	})
+
+	t.Run("should fail when the kind is not supported", func(t *testing.T) {
+		path := writeFile(t, t.TempDir(), "agent.yaml", "kind: Agent\nname: agent-1\n")
+
+		_, err := manifest.Read(path)
+		assert.ErrorContains(t, err, "kind 'Agent' of document 1 of '"+path+"' is not supported")
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("should marshal the object with its kind on top, dropping the read only fields", func(t *testing.T) {
+		object := map[string]interface{}{
+			"name":   "production",
+			"etag":   "4ed1b2c3",
+			"origin": map[string]string{"type": "gocd"},
+			"pipelines": []map[string]string{
+				{"name": "sample"},
+			},
+		}
+
+		data, err := manifest.Marshal(manifest.KindEnvironment, object, "origin")
+		require.NoError(t, err)
+		assert.Equal(t, "kind: Environment\nname: production\npipelines:\n- name: sample\n", string(data))
+
+		path := filepath.Join(t.TempDir(), "production.yaml")
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		documents, err := manifest.Read(path)
+		require.NoError(t, err)
+		require.Len(t, documents, 1)
+		assert.Equal(t, manifest.KindEnvironment, documents[0].Kind)
+		assert.JSONEq(t, `{"name": "production", "pipelines": [{"name": "sample"}]}`, string(documents[0].Object))
+	})
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("environments", "production.yaml"), manifest.Path(manifest.KindEnvironment, "production"))
+	assert.Equal(t, filepath.Join("server", "site-url.yaml"), manifest.Path(manifest.KindSiteURL, "site-url"))
+	assert.Equal(t, filepath.Join("pipelines", "feature_login.yaml"), manifest.Path(manifest.KindPipeline, "feature/login"))
+}
+
+func TestDocument_ReplaceSecrets(t *testing.T) {
+	t.Run("should replace the encrypted values nested anywhere in the object", func(t *testing.T) {
+		object := map[string]interface{}{
+			"id":   "docker",
+			"etag": "4ed1b2c3",
+			"properties": []map[string]interface{}{
+				{"key": "host", "value": "unix:///var/run/docker.sock"},
+				{"key": "token", "encrypted_value": "AES:source-token"},
+			},
+			"material": map[string]interface{}{
+				"attributes": map[string]string{"username": "admin", "encrypted_password": "AES:source-password"},
+			},
+		}
+
+		document, err := manifest.NewDocument(manifest.KindElasticAgentProfile, "profile 'old'", object)
+		require.NoError(t, err)
+
+		err = document.ReplaceSecrets(func(value string) (string, error) {
+			return strings.Replace(value, "source", "target", 1), nil
+		})
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+  "id": "docker",
+  "properties": [
+    {"key": "host", "value": "unix:///var/run/docker.sock"},
+    {"key": "token", "encrypted_value": "AES:target-token"}
+  ],
+  "material": {"attributes": {"username": "admin", "encrypted_password": "AES:target-password"}}
+}`, string(document.Object))
+	})
+
+	t.Run("should fail when the encrypted value could not be replaced", func(t *testing.T) {
+		document, err := manifest.NewDocument(manifest.KindAuthConfig, "profile 'old'", map[string]interface{}{
+			"properties": []map[string]string{{"key": "password", "encrypted_value": "AES:source"}},
+		})
+		require.NoError(t, err)
+
+		err = document.ReplaceSecrets(func(_ string) (string, error) {
+			return "", stderrors.New("cipher key is not set")
+		})
+		assert.EqualError(t, err, "replacing encrypted values of AuthConfig from profile 'old' errored with: cipher key is not set")
+	})
+}
@@ -0,0 +1,124 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+)
+
+const (
+	// Version is the version of the plan file format, plans of other versions are not applied.
+	// Plans of version 1 held the state of the resource in the output format they were made with, they are planned again with version 2.
+	Version = 2
+	// FromFileFlag is the flag that the desired state is passed with, it is set by CommandArgs and is not recorded among the flags.
+	FromFileFlag = "from-file"
+
+	filePermission = 0o600
+)
+
+// Plan is the update planned by a command run with --plan-out. It holds the state of the resource fetched from GoCD server
+// while planning, so that applying the plan could be refused when the resource was modified since.
+type Plan struct {
+	Version   int                 `json:"version,omitempty" yaml:"version,omitempty"`
+	Command   []string            `json:"command,omitempty" yaml:"command,omitempty"`
+	Args      []string            `json:"args,omitempty" yaml:"args,omitempty"`
+	Flags     map[string][]string `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Server    string              `json:"server,omitempty" yaml:"server,omitempty"`
+	Existing  string              `json:"existing,omitempty" yaml:"existing,omitempty"`
+	Desired   string              `json:"desired,omitempty" yaml:"desired,omitempty"`
+	Diff      string              `json:"diff,omitempty" yaml:"diff,omitempty"`
+	CreatedAt time.Time           `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	// Checked is set on the plans saved by the update commands that check the state of the resource before applying the changes,
+	// the plans without it are not applied.
+	Checked bool `json:"checked,omitempty" yaml:"checked,omitempty"`
+	// verified is set once the state of the resource is checked by Verify, while the plan is being applied.
+	verified bool
+}
+
+// Load reads the plan saved under the path passed.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan Plan
+	if err = json.Unmarshal(data, &plan); err != nil {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("reading plan '%s' errored with: %v", path, err)}
+	}
+
+	if plan.Version != Version {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("plan '%s' is of version '%d', only plans of version '%d' are supported", path, plan.Version, Version)}
+	}
+
+	if len(plan.Command) == 0 {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("plan '%s' does not have the command to apply it", path)}
+	}
+
+	if !plan.Checked {
+		return nil, &errors.ValidationError{Message: fmt.Sprintf("plan '%s' was not saved by a command that checks the state of the resource, it cannot be applied", path)}
+	}
+
+	return &plan, nil
+}
+
+// Save saves the plan under the path passed, the file is readable only by the user since the resources could hold secrets.
+func (plan *Plan) Save(path string) error {
+	plan.Version = Version
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, filePermission)
+}
+
+// Verify checks that the resource on GoCD server is still in the state it was in while planning, and that the desired state is the planned one.
+func (plan *Plan) Verify(existing, desired string) error {
+	if existing != plan.Existing {
+		return &errors.ConflictError{Message: fmt.Sprintf("the resource was modified on GoCD server after the plan was made at '%s', plan again",
+			plan.CreatedAt.Format(time.RFC3339))}
+	}
+
+	if desired != plan.Desired {
+		return &errors.ValidationError{Message: "the changes being applied are not the ones planned"}
+	}
+
+	plan.verified = true
+
+	return nil
+}
+
+// Verified returns true once the state of the resource was checked by Verify, the plans applied without it are not race free.
+func (plan *Plan) Verified() bool {
+	return plan.verified
+}
+
+// CommandArgs returns the args that runs the planned command, reading the desired state from the file passed.
+func (plan *Plan) CommandArgs(fromFile string) []string {
+	args := append(append([]string{}, plan.Command...), plan.Args...)
+
+	flagNames := make([]string, 0, len(plan.Flags))
+	for flagName := range plan.Flags {
+		flagNames = append(flagNames, flagName)
+	}
+
+	sort.Strings(flagNames)
+
+	for _, flagName := range flagNames {
+		if flagName == FromFileFlag {
+			continue
+		}
+
+		for _, value := range plan.Flags[flagName] {
+			args = append(args, fmt.Sprintf("--%s=%s", flagName, value))
+		}
+	}
+
+	return append(args, "--"+FromFileFlag, fromFile)
+}
@@ -0,0 +1,76 @@
+package plan_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	planned := &plan.Plan{
+		Command:   []string{"environment", "update"},
+		Flags:     map[string][]string{"name": {"production"}, "env-var": {"A", "B"}, "from-file": {"environment.yaml"}},
+		Server:    "https://gocd.central.com/go",
+		Existing:  `{"name":"production"}`,
+		Desired:   "name: production\npipelines: [sample]\n",
+		Diff:      "+pipelines: [sample]",
+		CreatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		Checked:   true,
+	}
+
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+
+	t.Run("should save and load the plan", func(t *testing.T) {
+		require.NoError(t, planned.Save(planPath))
+
+		info, err := os.Stat(planPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		loaded, err := plan.Load(planPath)
+		require.NoError(t, err)
+		assert.Equal(t, planned, loaded)
+	})
+
+	t.Run("should refuse the plans of other versions", func(t *testing.T) {
+		versionPath := filepath.Join(t.TempDir(), "plan.json")
+		require.NoError(t, os.WriteFile(versionPath, []byte(`{"version": 1, "command": ["environment", "update"]}`), 0o600))
+
+		_, err := plan.Load(versionPath)
+		assert.EqualError(t, err, "plan '"+versionPath+"' is of version '1', only plans of version '2' are supported")
+	})
+
+	t.Run("should refuse the plans not saved by the commands checking the state of the resource", func(t *testing.T) {
+		uncheckedPath := filepath.Join(t.TempDir(), "plan.json")
+		require.NoError(t, os.WriteFile(uncheckedPath, []byte(`{"version": 2, "command": ["backup", "delete-config"]}`), 0o600))
+
+		_, err := plan.Load(uncheckedPath)
+		assert.EqualError(t, err, "plan '"+uncheckedPath+"' was not saved by a command that checks the state of the resource, it cannot be applied")
+	})
+
+	t.Run("should verify the server state against the planned one", func(t *testing.T) {
+		assert.False(t, planned.Verified())
+		assert.NoError(t, planned.Verify(`{"name":"production"}`, planned.Desired))
+		assert.True(t, planned.Verified())
+
+		err := planned.Verify(`{"name":"staging"}`, planned.Desired)
+		var conflictError *errors.ConflictError
+		assert.ErrorAs(t, err, &conflictError)
+		assert.EqualError(t, err, "the resource was modified on GoCD server after the plan was made at '2024-01-01T10:00:00Z', plan again")
+
+		err = planned.Verify(planned.Existing, "name: staging\n")
+		var validationError *errors.ValidationError
+		assert.ErrorAs(t, err, &validationError)
+	})
+
+	t.Run("should return the args that runs the planned command, with the desired state passed only once", func(t *testing.T) {
+		expected := []string{"environment", "update", "--env-var=A", "--env-var=B", "--name=production", "--from-file", "desired.yaml"}
+		assert.Equal(t, expected, planned.CommandArgs("desired.yaml"))
+	})
+}
@@ -0,0 +1,170 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Version is the draft of JSON Schema the schemas are generated in.
+const Version = "http://json-schema.org/draft-07/schema#"
+
+// JSON Schema types of the values.
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Schema is a JSON Schema describing an object, as it is accepted by GoCD server.
+// A schema without a type accepts any value, ex: the fields whose values are not known ahead like plugin configurations.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Const       interface{}        `json:"const,omitempty" yaml:"const,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	// AdditionalProperties is false for the objects that accept only the properties declared, or the schema of the values of a map.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+}
+
+// Generate returns the JSON Schema of the value passed, derived from the JSON tags of its fields.
+// The objects accept only the fields declared, so that the misspelled or unknown fields are reported instead of being dropped.
+func Generate(title string, value interface{}) *Schema {
+	schema := generate(reflect.TypeOf(value), map[reflect.Type]bool{})
+	schema.Schema = Version
+	schema.Title = title
+
+	return schema
+}
+
+// SetKind declares the optional property holding the kind of the object, that accepts only the kinds passed.
+// ex: the kind set on top of the manifests, which is not a field of the object itself.
+func (schema *Schema) SetKind(key string, kinds ...string) {
+	if len(kinds) == 0 {
+		return
+	}
+
+	kindSchema := &Schema{Type: TypeString}
+	if len(kinds) == 1 {
+		kindSchema.Const = kinds[0]
+	} else {
+		for _, kind := range kinds {
+			kindSchema.Enum = append(kindSchema.Enum, kind)
+		}
+	}
+
+	if schema.Properties == nil {
+		schema.Properties = map[string]*Schema{}
+	}
+
+	schema.Properties[key] = kindSchema
+}
+
+func generate(valueType reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	if valueType == nil {
+		return &Schema{}
+	}
+
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	switch valueType {
+	case timeType:
+		return &Schema{Type: TypeString, Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch valueType.Kind() { //nolint:exhaustive
+	case reflect.Struct:
+		// the types referring to themselves accept any value where they are nested, rather than being expanded forever.
+		if visiting[valueType] {
+			return &Schema{Type: TypeObject}
+		}
+
+		visiting[valueType] = true
+		defer delete(visiting, valueType)
+
+		schema := &Schema{Type: TypeObject, Properties: map[string]*Schema{}, AdditionalProperties: false}
+		addProperties(schema, valueType, visiting)
+
+		return schema
+	case reflect.Map:
+		return &Schema{Type: TypeObject, AdditionalProperties: generate(valueType.Elem(), visiting)}
+	case reflect.Slice, reflect.Array:
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeString}
+		}
+
+		return &Schema{Type: TypeArray, Items: generate(valueType.Elem(), visiting)}
+	case reflect.String:
+		return &Schema{Type: TypeString}
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeInteger}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	default:
+		return &Schema{}
+	}
+}
+
+// addProperties adds the fields of the struct as the properties of the schema, the fields of the embedded structs are promoted as they are by encoding/json.
+func addProperties(schema *Schema, structType reflect.Type, visiting map[reflect.Type]bool) {
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && len(name) == 0 {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+
+			if embeddedType.Kind() == reflect.Struct {
+				addProperties(schema, embeddedType, visiting)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		if options == "string" || strings.Contains(options, ",string") {
+			schema.Properties[name] = &Schema{Type: TypeString}
+
+			continue
+		}
+
+		schema.Properties[name] = generate(field.Type, visiting)
+	}
+}
@@ -0,0 +1,197 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/nikhilsbhat/gocd-cli/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type links struct {
+	Self string `json:"self,omitempty"`
+}
+
+type job struct {
+	Name    string            `json:"name,omitempty"`
+	Timeout int               `json:"timeout,omitempty"`
+	Tasks   []task            `json:"tasks,omitempty"`
+	Config  map[string]string `json:"config,omitempty"`
+}
+
+type task struct {
+	Type       string                 `json:"type,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type pipeline struct {
+	links
+	Name       string          `json:"name,omitempty"`
+	Locked     bool            `json:"lock,omitempty"`
+	Ratio      float64         `json:"ratio,omitempty"`
+	Jobs       []job           `json:"jobs,omitempty"`
+	Created    time.Time       `json:"created,omitempty"`
+	Raw        json.RawMessage `json:"raw,omitempty"`
+	Parent     *pipeline       `json:"parent,omitempty"`
+	ETAG       string          `json:"-"`
+	unexported string
+}
+
+func TestGenerate(t *testing.T) {
+	generated := schema.Generate("Pipeline", pipeline{})
+
+	t.Run("should generate the schema of the object from its JSON tags", func(t *testing.T) {
+		assert.Equal(t, schema.Version, generated.Schema)
+		assert.Equal(t, "Pipeline", generated.Title)
+		assert.Equal(t, schema.TypeObject, generated.Type)
+		assert.Equal(t, false, generated.AdditionalProperties)
+
+		names := make([]string, 0)
+		for name := range generated.Properties {
+			names = append(names, name)
+		}
+
+		assert.ElementsMatch(t, []string{"self", "name", "lock", "ratio", "jobs", "created", "raw", "parent"}, names)
+		assert.Equal(t, schema.TypeBoolean, generated.Properties["lock"].Type)
+		assert.Equal(t, schema.TypeNumber, generated.Properties["ratio"].Type)
+		assert.Equal(t, "date-time", generated.Properties["created"].Format)
+		assert.Equal(t, &schema.Schema{}, generated.Properties["raw"])
+		assert.Equal(t, &schema.Schema{Type: schema.TypeObject}, generated.Properties["parent"])
+	})
+
+	t.Run("should generate the schema of the lists and maps from their items", func(t *testing.T) {
+		jobs := generated.Properties["jobs"]
+		assert.Equal(t, schema.TypeArray, jobs.Type)
+		assert.Equal(t, schema.TypeInteger, jobs.Items.Properties["timeout"].Type)
+		assert.Equal(t, &schema.Schema{Type: schema.TypeString}, jobs.Items.Properties["config"].AdditionalProperties)
+	})
+
+	t.Run("should be marshalled as JSON Schema", func(t *testing.T) {
+		out, err := json.Marshal(schema.Generate("Task", task{}))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Task",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "type": {"type": "string"},
+    "attributes": {"type": "object", "additionalProperties": {}}
+  }
+}`, string(out))
+	})
+
+	t.Run("should declare the kind of the object as an optional property accepting only the kinds set", func(t *testing.T) {
+		withKind := schema.Generate("Task", task{})
+		withKind.SetKind("kind", "Task")
+
+		out, err := json.Marshal(withKind.Properties["kind"])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type": "string", "const": "Task"}`, string(out))
+
+		withKind.SetKind("kind", "Task", "Job")
+		assert.Equal(t, []interface{}{"Task", "Job"}, withKind.Properties["kind"].Enum)
+		assert.Nil(t, withKind.Properties["kind"].Const)
+
+		assert.NoError(t, withKind.Validate([]byte("kind: Task\ntype: exec\n")))
+	})
+}
+
+func TestSchema_Validate(t *testing.T) {
+	generated := schema.Generate("Pipeline", pipeline{})
+
+	t.Run("should report the unknown fields with their line and column", func(t *testing.T) {
+		err := generated.Validate([]byte(`name: build
+jobs:
+  - name: compile
+    timout: 10
+    tasks:
+      - type: exec
+        attributes:
+          command: make
+        args: all
+self: http://localhost
+colour: blue
+`))
+
+		var validationError *errors.ValidationError
+		require.ErrorAs(t, err, &validationError)
+		assert.Equal(t, `object does not match the schema of Pipeline:
+line 4, column 5: unknown field 'jobs[0].timout', did you mean 'timeout'?
+line 9, column 9: unknown field 'jobs[0].tasks[0].args'
+line 11, column 1: unknown field 'colour'`, err.Error())
+	})
+
+	t.Run("should report the unknown fields of the objects passed as JSON", func(t *testing.T) {
+		err := generated.Validate([]byte(`{
+  "name": "build",
+  "lok": true
+}`))
+		assert.EqualError(t, err, `object does not match the schema of Pipeline:
+line 3, column 3: unknown field 'lok', did you mean 'lock'?`)
+	})
+
+	t.Run("should accept the objects with the fields declared only", func(t *testing.T) {
+		assert.NoError(t, generated.Validate([]byte(`{"name": "build", "jobs": [{"name": "compile", "config": {"any": "value"}}], "raw": {"any": 1}}`)))
+		assert.NoError(t, generated.Validate([]byte(`name: build
+parent:
+  anything: goes
+`)))
+	})
+
+	t.Run("should leave the errors in syntax to be reported while decoding", func(t *testing.T) {
+		assert.NoError(t, generated.Validate([]byte(`{"name": "build"`)))
+	})
+
+	t.Run("should not report the top level fields ignored", func(t *testing.T) {
+		assert.NoError(t, generated.Validate([]byte("kind: Pipeline\nname: build\n"), "kind"))
+		assert.EqualError(t, generated.Validate([]byte("name: build\njobs:\n  - name: compile\n    kind: Job\n"), "kind"), `object does not match the schema of Pipeline:
+line 4, column 5: unknown field 'jobs[0].kind'`)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should decode the objects in YAML by their JSON tags", func(t *testing.T) {
+		var decoded pipeline
+		require.NoError(t, schema.Decode([]byte(`kind: Pipeline
+name: build
+lock: true
+jobs:
+  - name: compile
+    timeout: 10
+    config:
+      os: linux
+`), &decoded, "kind"))
+
+		assert.Equal(t, "build", decoded.Name)
+		assert.True(t, decoded.Locked)
+		assert.Equal(t, []job{{Name: "compile", Timeout: 10, Config: map[string]string{"os": "linux"}}}, decoded.Jobs)
+	})
+
+	t.Run("should decode the objects in JSON", func(t *testing.T) {
+		var decoded pipeline
+		require.NoError(t, schema.Decode([]byte(`{"kind": "Pipeline", "name": "build", "ratio": 0.5}`), &decoded, "kind"))
+
+		assert.Equal(t, "build", decoded.Name)
+		assert.InDelta(t, 0.5, decoded.Ratio, 0)
+	})
+
+	t.Run("should fail on the fields that are not declared, without decoding the object", func(t *testing.T) {
+		var decoded pipeline
+		err := schema.Decode([]byte("name: build\nlocked: true\n"), &decoded)
+
+		var validationError *errors.ValidationError
+		require.ErrorAs(t, err, &validationError)
+		assert.Equal(t, `object does not match the schema of pipeline:
+line 2, column 1: unknown field 'locked', did you mean 'lock'?`, err.Error())
+		assert.Empty(t, decoded.Name)
+	})
+
+	t.Run("should report the errors in syntax", func(t *testing.T) {
+		var decoded pipeline
+		assert.Error(t, schema.Decode([]byte("name: [build"), &decoded))
+	})
+}
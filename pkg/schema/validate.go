@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	ghodssYAML "github.com/ghodss/yaml"
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"github.com/thoas/go-funk"
+	"gopkg.in/yaml.v3"
+)
+
+// maxSuggestionDistance is the number of edits within which a field declared is suggested for an unknown field, ex: timeout for timout.
+const maxSuggestionDistance = 2
+
+// Validate reads the object in YAML or JSON and reports the fields it has that are not declared in the schema, along with their line and column.
+// The fields are otherwise dropped silently while decoding, ex: a misspelled 'timout' would leave the timeout unset.
+// The top level fields passed as ignored are not reported, ex: the kind set on top of the manifests.
+// Errors in the syntax are not reported by Validate, but by decoding the object afterwards.
+func (schema *Schema) Validate(data []byte, ignored ...string) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil //nolint:nilerr
+	}
+
+	return schema.ValidateNode(&node, ignored...)
+}
+
+// Decode decodes the object in YAML or JSON into the value passed by its pointer, after validating it against the schema of the value.
+// Objects in YAML are decoded by the JSON tags of the value as well, the same fields the schema is generated from.
+// The top level fields passed as ignored are neither validated nor decoded, unless the value declares them.
+func Decode(data []byte, value interface{}, ignored ...string) error {
+	valueType := reflect.TypeOf(value)
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	if err := Generate(valueType.Name(), value).Validate(data, ignored...); err != nil {
+		return err
+	}
+
+	if json.Valid(data) {
+		return json.Unmarshal(data, value)
+	}
+
+	return ghodssYAML.Unmarshal(data, value)
+}
+
+// ValidateNode is Validate for the objects that are read already, the lines and the columns reported are the ones of the node passed.
+func (schema *Schema) ValidateNode(node *yaml.Node, ignored ...string) error {
+	problems := make([]string, 0)
+
+	validate(node, schema, "", ignored, &problems)
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return &errors.ValidationError{Message: fmt.Sprintf("object does not match the schema of %s:\n%s", schema.Title, strings.Join(problems, "\n"))}
+}
+
+func validate(node *yaml.Node, schema *Schema, path string, ignored []string, problems *[]string) {
+	if node == nil || schema == nil {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, content := range node.Content {
+			validate(content, schema, path, ignored, problems)
+		}
+	case yaml.AliasNode:
+		validate(node.Alias, schema, path, ignored, problems)
+	case yaml.MappingNode:
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			keyNode, valueNode := node.Content[index], node.Content[index+1]
+			if keyNode.Tag == "!!merge" || (len(path) == 0 && funk.ContainsString(ignored, keyNode.Value)) {
+				continue
+			}
+
+			fieldPath := keyNode.Value
+			if len(path) != 0 {
+				fieldPath = path + "." + keyNode.Value
+			}
+
+			if property, ok := schema.Properties[keyNode.Value]; ok {
+				validate(valueNode, property, fieldPath, nil, problems)
+
+				continue
+			}
+
+			switch additional := schema.AdditionalProperties.(type) {
+			case *Schema:
+				validate(valueNode, additional, fieldPath, nil, problems)
+			case bool:
+				if additional {
+					continue
+				}
+
+				problem := fmt.Sprintf("line %d, column %d: unknown field '%s'", keyNode.Line, keyNode.Column, fieldPath)
+
+				if suggestion := suggest(keyNode.Value, schema.Properties); len(suggestion) != 0 {
+					problem = fmt.Sprintf("%s, did you mean '%s'?", problem, suggestion)
+				}
+
+				*problems = append(*problems, problem)
+			}
+		}
+	case yaml.SequenceNode:
+		for index, value := range node.Content {
+			validate(value, schema.Items, fmt.Sprintf("%s[%d]", path, index), nil, problems)
+		}
+	}
+}
+
+// suggest returns the property closest to the unknown field, when it is within maxSuggestionDistance edits of it.
+func suggest(field string, properties map[string]*Schema) string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var suggestion string
+
+	closest := maxSuggestionDistance + 1
+
+	for _, name := range names {
+		if distance := getDistance(strings.ToLower(field), strings.ToLower(name)); distance < closest {
+			suggestion, closest = name, distance
+		}
+	}
+
+	return suggestion
+}
+
+// getDistance returns the Levenshtein distance between the strings, the number of edits to turn one into the other.
+func getDistance(from, to string) int {
+	previous := make([]int, len(to)+1)
+	for index := range previous {
+		previous[index] = index
+	}
+
+	for fromIndex := 1; fromIndex <= len(from); fromIndex++ {
+		current := make([]int, len(to)+1)
+		current[0] = fromIndex
+
+		for toIndex := 1; toIndex <= len(to); toIndex++ {
+			cost := 1
+			if from[fromIndex-1] == to[toIndex-1] {
+				cost = 0
+			}
+
+			current[toIndex] = min(previous[toIndex]+1, current[toIndex-1]+1, previous[toIndex-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(to)]
+}
@@ -0,0 +1,283 @@
+package skeleton
+
+import (
+	"sort"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+)
+
+// Skeleton is the template of an object accepted as input, along with the command that reads it.
+type Skeleton struct {
+	// Command is the command that reads the object via --from-file, ex: configrepo create|update.
+	Command string
+	// Template is the object in YAML, with the comments describing its fields.
+	Template string
+	// Value is the type the object is decoded into by the command.
+	Value interface{}
+}
+
+// skeletons are the templates of the objects accepted by the create/update commands, by their kind.
+// The kinds are the ones of the manifests where the object could be declared in them, ex: Pipeline.
+// The templates hold the fields that are required or commonly set, with the values to be replaced.
+var skeletons = map[string]Skeleton{
+	manifest.KindAgent: {
+		Command: "agents update",
+		Template: `# uuid of the agent to be updated, as listed by 'gocd-cli agents get-all'.
+uuid: adb9540a-b954-4571-9d9b-2f330739d4da
+hostname: agent-01
+# Enabled or Disabled, the agents disabled are not assigned any jobs.
+agent_config_state: Enabled
+# resources of the agent, the jobs requiring them are assigned to it.
+resources:
+  - docker
+  - linux
+environments:
+  - production
+`,
+		Value: gocd.Agent{},
+	},
+	manifest.KindArtifactStore: {
+		Command: "artifact create-store|update-store",
+		Template: `id: docker-registry
+# artifact plugin the store is of, the properties below are specific to it. ex: the ones of the docker registry artifact plugin.
+plugin_id: cd.go.artifact.docker.registry
+properties:
+  - key: RegistryURL
+    value: https://registry.example.com
+  - key: RegistryType
+    value: other
+  - key: Username
+    value: gocd
+  - key: Password
+    # encrypt the secrets with 'gocd-cli encryption encrypt' and set them as encrypted_value instead.
+    encrypted_value: AES:replace-with-the-encrypted-password
+`,
+		Value: gocd.CommonConfig{},
+	},
+	manifest.KindAuthConfig: {
+		Command: "authorization create|update",
+		Template: `id: password-file
+# authorization plugin the config is of, the properties below are specific to it. ex: the ones of the password file plugin.
+plugin_id: cd.go.authentication.passwordfile
+# set this to true to allow only the users already known to GoCD server to login.
+allow_only_known_users_to_login: false
+properties:
+  - key: PasswordFilePath
+    value: /godata/config/password.properties
+`,
+		Value: gocd.CommonConfig{},
+	},
+	manifest.KindClusterProfile: {
+		Command: "cluster-profile create|update",
+		Template: `id: docker-cluster
+# elastic agent plugin the cluster is of, the properties below are specific to it. ex: the ones of the docker elastic agent plugin.
+plugin_id: cd.go.contrib.elastic-agent.docker
+properties:
+  - key: go_server_url
+    value: https://gocd.example.com/go
+  - key: docker_uri
+    value: unix:///var/run/docker.sock
+  - key: max_docker_containers
+    value: "10"
+`,
+		Value: gocd.CommonConfig{},
+	},
+	manifest.KindConfigRepo: {
+		Command: "configrepo create|update",
+		Template: `# id of the config repo, it should be unique across the config repos.
+id: sample-config-repo
+# plugin that parses the pipelines defined in the repository. ex: yaml.config.plugin, json.config.plugin, cd.go.contrib.plugins.configrepo.groovy
+plugin_id: yaml.config.plugin
+material:
+  # git, hg, svn, p4 or tfs.
+  type: git
+  attributes:
+    url: https://github.com/example/pipelines.git
+    branch: main
+    # set this to false when the changes to the repository are notified to GoCD server by a webhook.
+    auto_update: true
+    # credentials of the repository when it is private, encrypt the password with 'gocd-cli encryption encrypt'.
+    # username: gocd
+    # encrypted_password: AES:replace-with-the-encrypted-password
+# settings of the plugin, ex: the pattern of the files holding the pipelines for yaml.config.plugin.
+configuration:
+  - key: file_pattern
+    value: "**/*.gocd.yaml"
+# entities that the pipelines defined in the repository are allowed to refer to.
+rules:
+  - directive: allow
+    action: refer
+    type: pipeline_group
+    resource: "*"
+`,
+		Value: gocd.ConfigRepo{},
+	},
+	manifest.KindElasticAgentProfile: {
+		Command: "elastic-agent-profile create|update",
+		Template: `id: docker-small
+# cluster profile the agents are launched in, the properties below are specific to its plugin. ex: the ones of the docker elastic agent plugin.
+cluster_profile_id: docker-cluster
+properties:
+  - key: Image
+    value: gocd/gocd-agent-alpine-3.19:v24.1.0
+  - key: Environment
+    value: |
+      JAVA_HOME=/opt/java
+  - key: MaxMemory
+    value: 2G
+`,
+		Value: gocd.CommonConfig{},
+	},
+	manifest.KindEnvironment: {
+		Command: "environment create|update",
+		Template: `name: production
+# pipelines in the environment, they could be run only by the agents in it.
+pipelines:
+  - name: sample-pipeline
+# variables set for the jobs of the pipelines in the environment.
+environment_variables:
+  - name: DEPLOY_ENV
+    value: production
+  - name: DB_PASSWORD
+    secure: true
+    # encrypt the secrets with 'gocd-cli encryption encrypt'.
+    encrypted_value: AES:replace-with-the-encrypted-value
+`,
+		Value: gocd.Environment{},
+	},
+	manifest.KindPipeline: {
+		Command: "pipeline create|update",
+		Template: `# group the pipeline is created in.
+group: sample-group
+name: sample-pipeline
+label_template: ${COUNT}
+# lockOnFailure, unlockWhenFinished or none.
+lock_behavior: none
+materials:
+  - type: git
+    attributes:
+      url: https://github.com/example/app.git
+      branch: main
+      auto_update: true
+stages:
+  - name: build
+    jobs:
+      - name: compile
+        tasks:
+          - type: exec
+            attributes:
+              command: make
+              arguments:
+                - build
+`,
+		Value: gocd.PipelineConfig{},
+	},
+	manifest.KindPipelineGroup: {
+		Command: "pipeline-group create|update",
+		Template: `name: sample-group
+# users and roles allowed to view, operate and administer the pipelines in the group, everyone is allowed when it is not set.
+authorization:
+  view:
+    users:
+      - alice
+    roles:
+      - developers
+  operate:
+    roles:
+      - developers
+  admins:
+    users:
+      - alice
+`,
+		Value: gocd.PipelineGroup{},
+	},
+	manifest.KindPluginSettings: {
+		Command: "plugin create-settings|update-settings",
+		Template: `# id of the plugin the settings are of, the configuration below is specific to it.
+plugin_id: com.example.plugin
+configuration:
+  - key: api_url
+    value: https://api.example.com
+  - key: api_token
+    # encrypt the secrets with 'gocd-cli encryption encrypt'.
+    encrypted_value: AES:replace-with-the-encrypted-token
+`,
+		Value: gocd.PluginSettings{},
+	},
+	manifest.KindRole: {
+		Command: "roles create|update",
+		Template: `name: developers
+# gocd for the roles whose users are set below, plugin for the ones whose users are resolved by an authorization plugin.
+type: gocd
+attributes:
+  users:
+    - alice
+    - bob
+# with type plugin, the attributes are the authorization config and the properties of its plugin instead. ex:
+# attributes:
+#   auth_config_id: ldap
+#   properties:
+#     - key: UserGroupMembershipAttribute
+#       value: memberOf
+# permissions of the users in the role.
+policy:
+  - permission: allow
+    action: view
+    type: environment
+    resource: "*"
+`,
+		Value: gocd.Role{},
+	},
+	manifest.KindSchedule: {
+		Command: "pipeline schedule",
+		Template: `# values overriding the environment variables of the pipeline, for this run only.
+environment_variables:
+  - name: VERSION
+    value: 1.2.3
+    secure: false
+# revisions of the materials to run the pipeline with, by the fingerprint of the material. the latest ones are run when it is not set.
+materials:
+  - fingerprint: replace-with-the-fingerprint-of-the-material
+    revision: replace-with-the-revision
+# set this to false to run with the revisions of the materials last fetched by GoCD server.
+update_materials_before_scheduling: true
+`,
+		Value: gocd.Schedule{},
+	},
+	manifest.KindUser: {
+		Command: "user create|update",
+		Template: `# login name of the user, as known to the authorization plugin.
+login_name: alice
+`,
+		Value: gocd.User{},
+	},
+	manifest.KindUsersBulkDelete: {
+		Command: "user delete-bulk",
+		Template: `# login names of the users to be deleted, the users should be disabled before they could be deleted.
+users:
+  - alice
+  - bob
+`,
+		Value: map[string][]string{},
+	},
+}
+
+// Get returns the skeleton of the kind passed, the kind should be one of Kinds.
+func Get(kind string) (Skeleton, bool) {
+	objectSkeleton, ok := skeletons[kind]
+
+	return objectSkeleton, ok
+}
+
+// Kinds returns the kinds that have a skeleton, sorted by their name.
+func Kinds() []string {
+	kinds := make([]string, 0, len(skeletons))
+	for kind := range skeletons {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	return kinds
+}
@@ -0,0 +1,44 @@
+package skeleton_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/manifest"
+	"github.com/nikhilsbhat/gocd-cli/pkg/schema"
+	"github.com/nikhilsbhat/gocd-cli/pkg/skeleton"
+	"github.com/nikhilsbhat/gocd-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkeletons(t *testing.T) {
+	kinds := skeleton.Kinds()
+	require.NotEmpty(t, kinds)
+	assert.True(t, sort.StringsAreSorted(kinds))
+
+	for _, kind := range kinds {
+		t.Run("template of "+kind+" should decode strictly into its type", func(t *testing.T) {
+			objectSkeleton, ok := skeleton.Get(kind)
+			require.True(t, ok)
+			require.NotNil(t, objectSkeleton.Value)
+
+			value := reflect.New(reflect.TypeOf(objectSkeleton.Value))
+			require.NoError(t, schema.Decode([]byte(objectSkeleton.Template), value.Interface()))
+			assert.False(t, value.Elem().IsZero(), "template of %s should set the fields of its type", kind)
+		})
+	}
+
+	t.Run("should be looked up by the kinds of the manifests", func(t *testing.T) {
+		objectSkeleton, ok := skeleton.Get(manifest.KindPipeline)
+		require.True(t, ok)
+		assert.Equal(t, gocd.PipelineConfig{}, objectSkeleton.Value)
+		assert.NotContains(t, kinds, "PipelineConfig")
+	})
+
+	t.Run("should not return the kinds that do not have a skeleton", func(t *testing.T) {
+		_, ok := skeleton.Get("Unknown")
+		assert.False(t, ok)
+	})
+}
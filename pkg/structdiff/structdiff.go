@@ -0,0 +1,401 @@
+package structdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Operations of the changes identified by Diff.
+const (
+	OperationAdded    = "added"
+	OperationRemoved  = "removed"
+	OperationModified = "modified"
+)
+
+// setFields are the fields holding the lists whose order is not significant to GoCD server, ex: resources of an agent.
+var setFields = []string{
+	"resources", "environments", "rules", "users", "roles", "agents", "pipelines", "groups",
+	"environment_variables", "secure_variables", "properties", "configuration",
+}
+
+// keyFields are the fields identifying the objects in the lists, in the order of their preference.
+var keyFields = []string{"name", "id", "key"}
+
+// ignoredFields are the fields set by GoCD server, that are never a change made by the user.
+var ignoredFields = []string{"_links", "etag", "origin", "origins", "config_repo_parse_info"}
+
+// Change is a change to a field of the object, identified by its path. ex: environment_variables[name=ENV].value.
+type Change struct {
+	Path      string      `json:"path" yaml:"path"`
+	Operation string      `json:"operation" yaml:"operation"`
+	Old       interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New       interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// Conflict is a field changed in both the objects merged by Merge, to different values.
+type Conflict struct {
+	Path   string      `json:"path" yaml:"path"`
+	Base   interface{} `json:"base,omitempty" yaml:"base,omitempty"`
+	Theirs interface{} `json:"theirs,omitempty" yaml:"theirs,omitempty"`
+	Ours   interface{} `json:"ours,omitempty" yaml:"ours,omitempty"`
+}
+
+// Diff compares the objects structurally and returns the changes from the old to the new one, sorted by their path.
+// The objects are compared as they are sent to GoCD server, so the order of the keys, the fields left to their defaults
+// and the order of the lists that are sets (ex: resources, environments, rules) are not considered as changes.
+func Diff(oldObject, newObject interface{}) ([]Change, error) {
+	oldValue, err := normalize(oldObject)
+	if err != nil {
+		return nil, err
+	}
+
+	newValue, err := normalize(newObject)
+	if err != nil {
+		return nil, err
+	}
+
+	// an object compared against nothing, ex: a resource being created, is compared field by field rather than as a whole.
+	if _, ok := newValue.(map[string]interface{}); ok && oldValue == nil {
+		oldValue = make(map[string]interface{})
+	}
+
+	if _, ok := oldValue.(map[string]interface{}); ok && newValue == nil {
+		newValue = make(map[string]interface{})
+	}
+
+	changes := make([]Change, 0)
+	compare("", "", oldValue, newValue, &changes)
+
+	return changes, nil
+}
+
+// String returns the changes in a form readable by humans, one change per line.
+func String(changes []Change) string {
+	lines := make([]string, 0, len(changes))
+
+	for _, change := range changes {
+		switch change.Operation {
+		case OperationAdded:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", change.Path, toString(change.New)))
+		case OperationRemoved:
+			lines = append(lines, fmt.Sprintf("- %s: %s", change.Path, toString(change.Old)))
+		default:
+			lines = append(lines, fmt.Sprintf("~ %s: %s => %s", change.Path, toString(change.Old), toString(change.New)))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Merge merges the changes made to the base object in theirs and ours, three-way and field by field. ex: the changes made on GoCD server
+// and the ones made in the input, since the version both are based on. Changes to different fields are merged, while the fields changed
+// in both to different values are returned as conflicts. The lists that are sets are merged item by item, the other lists are merged
+// by their index when none of them changed the length of the list, and as a whole otherwise.
+// The merged object is returned as the generic value it is sent to GoCD server as, without the fields left empty.
+func Merge(base, theirs, ours interface{}) (interface{}, []Conflict, error) {
+	values := make([]interface{}, 0, 3) //nolint:mnd
+
+	for _, object := range []interface{}{base, theirs, ours} {
+		value, err := normalize(object)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	conflicts := make([]Conflict, 0)
+	merged := merge("", "", values[0], values[1], values[2], &conflicts)
+
+	return merged, conflicts, nil
+}
+
+// ConflictsString returns the conflicts in a form readable by humans, one conflict per line.
+func ConflictsString(conflicts []Conflict) string {
+	lines := make([]string, 0, len(conflicts))
+
+	for _, conflict := range conflicts {
+		lines = append(lines, fmt.Sprintf("! %s: %s => theirs: %s, ours: %s", conflict.Path, toString(conflict.Base), toString(conflict.Theirs), toString(conflict.Ours)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func merge(path, field string, base, theirs, ours interface{}, conflicts *[]Conflict) interface{} {
+	switch {
+	case reflect.DeepEqual(theirs, ours), reflect.DeepEqual(base, theirs):
+		return ours
+	case reflect.DeepEqual(base, ours):
+		return theirs
+	}
+
+	baseMap, baseIsMap := base.(map[string]interface{})
+	theirsMap, theirsIsMap := theirs.(map[string]interface{})
+	oursMap, oursIsMap := ours.(map[string]interface{})
+
+	if theirsIsMap && oursIsMap && (baseIsMap || base == nil) {
+		merged := make(map[string]interface{})
+
+		for _, key := range getKeys(baseMap, theirsMap, oursMap) {
+			if value := merge(joinPath(path, key), key, baseMap[key], theirsMap[key], oursMap[key], conflicts); value != nil {
+				merged[key] = value
+			}
+		}
+
+		if len(merged) == 0 {
+			return nil
+		}
+
+		return merged
+	}
+
+	baseList, baseIsList := base.([]interface{})
+	theirsList, theirsIsList := theirs.([]interface{})
+	oursList, oursIsList := ours.([]interface{})
+
+	if theirsIsList && oursIsList && (baseIsList || base == nil) {
+		if contains(setFields, field) {
+			return mergeSet(path, baseList, theirsList, oursList, conflicts)
+		}
+
+		if len(baseList) == len(theirsList) && len(baseList) == len(oursList) {
+			merged := make([]interface{}, 0, len(oursList))
+
+			for index := range oursList {
+				if value := merge(fmt.Sprintf("%s[%d]", path, index), "", baseList[index], theirsList[index], oursList[index], conflicts); value != nil {
+					merged = append(merged, value)
+				}
+			}
+
+			return merged
+		}
+	}
+
+	*conflicts = append(*conflicts, Conflict{Path: path, Base: base, Theirs: theirs, Ours: ours})
+
+	return ours
+}
+
+// mergeSet merges the lists ignoring the order of their items, the items are matched by their key field if they have one.
+func mergeSet(path string, baseList, theirsList, oursList []interface{}, conflicts *[]Conflict) interface{} {
+	baseItems, theirsItems, oursItems := getItems(baseList), getItems(theirsList), getItems(oursList)
+
+	merged := make([]interface{}, 0, len(oursList))
+
+	for _, identity := range getKeys(baseItems, theirsItems, oursItems) {
+		item := merge(fmt.Sprintf("%s[%s]", path, identity), "", baseItems[identity], theirsItems[identity], oursItems[identity], conflicts)
+		if item != nil {
+			merged = append(merged, item)
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	return merged
+}
+
+// normalize returns the object as the generic value it is sent to GoCD server as, without the fields left empty.
+func normalize(object interface{}) (interface{}, error) {
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(objectJSON, &value); err != nil {
+		return nil, err
+	}
+
+	return normalizeValue("", value), nil
+}
+
+func normalizeValue(field string, value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(typedValue))
+
+		for key, item := range typedValue {
+			if contains(ignoredFields, key) {
+				continue
+			}
+
+			if normalizedItem := normalizeValue(key, item); normalizedItem != nil {
+				normalized[key] = normalizedItem
+			}
+		}
+
+		if len(normalized) == 0 {
+			return nil
+		}
+
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, 0, len(typedValue))
+
+		for _, item := range typedValue {
+			if normalizedItem := normalizeValue("", item); normalizedItem != nil {
+				normalized = append(normalized, normalizedItem)
+			}
+		}
+
+		if len(normalized) == 0 {
+			return nil
+		}
+
+		if contains(setFields, field) {
+			sort.SliceStable(normalized, func(i, j int) bool {
+				return getIdentity(normalized[i]) < getIdentity(normalized[j])
+			})
+		}
+
+		return normalized
+	default:
+		if value == nil || reflect.ValueOf(value).IsZero() {
+			return nil
+		}
+
+		return value
+	}
+}
+
+func compare(path, field string, oldValue, newValue interface{}, changes *[]Change) {
+	switch {
+	case oldValue == nil && newValue == nil:
+		return
+	case oldValue == nil:
+		*changes = append(*changes, Change{Path: path, Operation: OperationAdded, New: newValue})
+
+		return
+	case newValue == nil:
+		*changes = append(*changes, Change{Path: path, Operation: OperationRemoved, Old: oldValue})
+
+		return
+	}
+
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+
+	if oldIsMap && newIsMap {
+		for _, key := range getKeys(oldMap, newMap) {
+			compare(joinPath(path, key), key, oldMap[key], newMap[key], changes)
+		}
+
+		return
+	}
+
+	oldList, oldIsList := oldValue.([]interface{})
+	newList, newIsList := newValue.([]interface{})
+
+	if oldIsList && newIsList {
+		if contains(setFields, field) {
+			compareSet(path, oldList, newList, changes)
+
+			return
+		}
+
+		for index := 0; index < len(oldList) || index < len(newList); index++ {
+			compare(fmt.Sprintf("%s[%d]", path, index), "", getItem(oldList, index), getItem(newList, index), changes)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, Change{Path: path, Operation: OperationModified, Old: oldValue, New: newValue})
+	}
+}
+
+// compareSet compares the lists ignoring the order of their items, the items are matched by their key field if they have one.
+func compareSet(path string, oldList, newList []interface{}, changes *[]Change) {
+	oldItems, newItems := getItems(oldList), getItems(newList)
+
+	for _, identity := range getKeys(oldItems, newItems) {
+		compare(fmt.Sprintf("%s[%s]", path, identity), "", oldItems[identity], newItems[identity], changes)
+	}
+}
+
+// getItems returns the items of the list by their identity.
+func getItems(list []interface{}) map[string]interface{} {
+	items := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		items[getIdentity(item)] = item
+	}
+
+	return items
+}
+
+// getIdentity returns the identity of the item in a list, ex: name=production. Items without a key field are identified by their value.
+func getIdentity(item interface{}) string {
+	if object, ok := item.(map[string]interface{}); ok {
+		for _, keyField := range keyFields {
+			if key, ok := object[keyField].(string); ok {
+				return fmt.Sprintf("%s=%s", keyField, key)
+			}
+		}
+	}
+
+	return toString(item)
+}
+
+// getKeys returns the keys present in any of the maps, sorted.
+func getKeys(maps ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+
+	for _, object := range maps {
+		for key := range object {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func getItem(list []interface{}, index int) interface{} {
+	if index < len(list) {
+		return list[index]
+	}
+
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func toString(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(valueJSON)
+}
+
+func contains(fields []string, field string) bool {
+	for _, item := range fields {
+		if item == field {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,165 @@
+package structdiff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/structdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type environmentVariable struct {
+	Name   string `json:"name,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Secure bool   `json:"secure,omitempty"`
+}
+
+type agent struct {
+	ETAG                 string                `json:"etag,omitempty"`
+	Hostname             string                `json:"hostname,omitempty"`
+	Sandbox              string                `json:"sandbox,omitempty"`
+	Resources            []string              `json:"resources,omitempty"`
+	EnvironmentVariables []environmentVariable `json:"environment_variables,omitempty"`
+	Stages               []environmentVariable `json:"stages,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	existing := agent{
+		ETAG:      "4ed1b2c3",
+		Hostname:  "agent-1",
+		Resources: []string{"docker", "linux"},
+		EnvironmentVariables: []environmentVariable{
+			{Name: "ENV", Value: "staging"},
+			{Name: "REGION", Value: "eu-west-1"},
+		},
+	}
+
+	t.Run("should not report changes to the order of the keys, the sets and the fields left to their defaults", func(t *testing.T) {
+		var desired agent
+		require.NoError(t, json.Unmarshal([]byte(`{
+  "environment_variables": [{"value": "eu-west-1", "name": "REGION"}, {"name": "ENV", "value": "staging", "secure": false}],
+  "resources": ["linux", "docker"],
+  "sandbox": "",
+  "hostname": "agent-1"
+}`), &desired))
+
+		changes, err := structdiff.Diff(existing, desired)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("should report the changes by the path of the fields", func(t *testing.T) {
+		desired := agent{
+			Hostname:  "agent-2",
+			Sandbox:   "/var/lib/go-agent",
+			Resources: []string{"linux", "windows"},
+			EnvironmentVariables: []environmentVariable{
+				{Name: "REGION", Value: "eu-west-1"},
+				{Name: "ENV", Value: "production"},
+			},
+		}
+
+		changes, err := structdiff.Diff(existing, desired)
+		require.NoError(t, err)
+
+		expected := []structdiff.Change{
+			{Path: "environment_variables[name=ENV].value", Operation: structdiff.OperationModified, Old: "staging", New: "production"},
+			{Path: "hostname", Operation: structdiff.OperationModified, Old: "agent-1", New: "agent-2"},
+			{Path: "resources[docker]", Operation: structdiff.OperationRemoved, Old: "docker"},
+			{Path: "resources[windows]", Operation: structdiff.OperationAdded, New: "windows"},
+			{Path: "sandbox", Operation: structdiff.OperationAdded, New: "/var/lib/go-agent"},
+		}
+		assert.Equal(t, expected, changes)
+
+		assert.Equal(t, `~ environment_variables[name=ENV].value: staging => production
+~ hostname: agent-1 => agent-2
+- resources[docker]: docker
++ resources[windows]: windows
++ sandbox: /var/lib/go-agent`, structdiff.String(changes))
+	})
+
+	t.Run("should report the changes to the order of the lists that are not sets", func(t *testing.T) {
+		oldAgent := agent{Stages: []environmentVariable{{Name: "build"}, {Name: "test"}}}
+		newAgent := agent{Stages: []environmentVariable{{Name: "test"}, {Name: "build"}}}
+
+		changes, err := structdiff.Diff(oldAgent, newAgent)
+		require.NoError(t, err)
+		assert.Equal(t, "~ stages[0].name: build => test\n~ stages[1].name: test => build", structdiff.String(changes))
+	})
+
+	t.Run("should report the fields of the objects compared against nothing one by one", func(t *testing.T) {
+		changes, err := structdiff.Diff(nil, agent{Hostname: "agent-1", Resources: []string{"linux"}})
+		require.NoError(t, err)
+		assert.Equal(t, "+ hostname: agent-1\n+ resources: [\"linux\"]", structdiff.String(changes))
+
+		changes, err = structdiff.Diff(agent{Hostname: "agent-1"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "- hostname: agent-1", structdiff.String(changes))
+	})
+}
+
+func TestMerge(t *testing.T) {
+	base := agent{
+		Hostname:  "agent-1",
+		Sandbox:   "/var/lib/go-agent",
+		Resources: []string{"docker", "linux"},
+		EnvironmentVariables: []environmentVariable{
+			{Name: "ENV", Value: "staging"},
+			{Name: "REGION", Value: "eu-west-1"},
+		},
+		Stages: []environmentVariable{{Name: "build"}, {Name: "test"}},
+	}
+
+	t.Run("should merge the changes made to different fields", func(t *testing.T) {
+		theirs := base
+		theirs.Hostname = "agent-2"
+		theirs.Resources = []string{"docker", "linux", "windows"}
+		theirs.EnvironmentVariables = []environmentVariable{{Name: "ENV", Value: "production"}, {Name: "REGION", Value: "eu-west-1"}}
+
+		ours := base
+		ours.Sandbox = "/opt/go-agent"
+		ours.Resources = []string{"linux"}
+		ours.EnvironmentVariables = []environmentVariable{{Name: "ENV", Value: "staging"}, {Name: "REGION", Value: "eu-west-1"}, {Name: "ZONE", Value: "a"}}
+		ours.Stages = []environmentVariable{{Name: "build"}, {Name: "deploy"}}
+
+		merged, conflicts, err := structdiff.Merge(base, theirs, ours)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+
+		expected := agent{
+			Hostname:  "agent-2",
+			Sandbox:   "/opt/go-agent",
+			Resources: []string{"linux", "windows"},
+			EnvironmentVariables: []environmentVariable{
+				{Name: "ENV", Value: "production"},
+				{Name: "REGION", Value: "eu-west-1"},
+				{Name: "ZONE", Value: "a"},
+			},
+			Stages: []environmentVariable{{Name: "build"}, {Name: "deploy"}},
+		}
+
+		changes, err := structdiff.Diff(expected, merged)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("should report the fields changed on both sides to different values as conflicts", func(t *testing.T) {
+		theirs := base
+		theirs.Hostname = "agent-2"
+		theirs.EnvironmentVariables = []environmentVariable{{Name: "ENV", Value: "production"}, {Name: "REGION", Value: "eu-west-1"}}
+		theirs.Stages = []environmentVariable{{Name: "build"}}
+
+		ours := base
+		ours.Hostname = "agent-2"
+		ours.EnvironmentVariables = []environmentVariable{{Name: "ENV", Value: "qa"}, {Name: "REGION", Value: "eu-west-1"}}
+		ours.Stages = []environmentVariable{{Name: "build"}, {Name: "test"}, {Name: "deploy"}}
+
+		_, conflicts, err := structdiff.Merge(base, theirs, ours)
+		require.NoError(t, err)
+
+		assert.Equal(t, `! environment_variables[name=ENV].value: staging => theirs: production, ours: qa
+! stages: [{"name":"build"},{"name":"test"}] => theirs: [{"name":"build"}], ours: [{"name":"build"},{"name":"test"},{"name":"deploy"}]`,
+			structdiff.ConflictsString(conflicts))
+	})
+}
@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/cache"
+)
+
+// Cacher returns a Middleware that serves the successful GET calls from the cache passed, until they expire.
+// Calls other than GET could modify the resources on GoCD server, hence the cache is cleared when they succeed.
+// Cached responses are keyed by the credentials used, so that the responses are not shared across users.
+// GET calls with the header 'Cache-Control: no-cache' are always made to GoCD server, and their responses refresh the cache.
+func Cacher(responseCache *cache.Cache) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				resp, err := next.RoundTrip(req)
+				if err == nil && resp.StatusCode < http.StatusBadRequest {
+					if err = responseCache.Clear(); err != nil {
+						return nil, err
+					}
+				}
+
+				return resp, err
+			}
+
+			key := responseCacheKey(req)
+
+			var cached RecordedResponse
+			if req.Header.Get("Cache-Control") != "no-cache" && responseCache.Get(key, &cached) {
+				return newResponse(req, cached), nil
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				return resp, err
+			}
+
+			responseBody, err := readBody(&resp.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			header := resp.Header.Clone()
+			header.Del("Set-Cookie")
+
+			// failing to cache should not fail the call, the response would be fetched from GoCD server next time.
+			_ = responseCache.Set(key, RecordedResponse{StatusCode: resp.StatusCode, Header: header, Body: string(responseBody)})
+
+			return resp, nil
+		})
+	}
+}
+
+func responseCacheKey(req *http.Request) string {
+	credentials := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+
+	return strings.Join([]string{req.Method, req.URL.String(), req.Header.Get("Accept"), hex.EncodeToString(credentials[:])}, "\x00")
+}
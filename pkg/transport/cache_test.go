@@ -0,0 +1,86 @@
+package transport_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/cache"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		calls++
+		if req.URL.Path == "/go/api/admin/pipelines/missing" {
+			http.NotFound(writer, req)
+
+			return
+		}
+		_, _ = fmt.Fprintf(writer, `{"call": %d}`, calls)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: transport.Chain(http.DefaultTransport, transport.Cacher(cache.New(t.TempDir(), time.Minute)))}
+
+	doCall := func(t *testing.T, method, path, authorization string) string {
+		t.Helper()
+
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", authorization)
+
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	t.Run("should serve the repeated GET calls from cache", func(t *testing.T) {
+		assert.JSONEq(t, `{"call": 1}`, doCall(t, http.MethodGet, "/go/api/admin/pipelines/sample", "Bearer token-1"))
+		assert.JSONEq(t, `{"call": 1}`, doCall(t, http.MethodGet, "/go/api/admin/pipelines/sample", "Bearer token-1"))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should not share the cached responses across credentials", func(t *testing.T) {
+		assert.JSONEq(t, `{"call": 2}`, doCall(t, http.MethodGet, "/go/api/admin/pipelines/sample", "Bearer token-2"))
+	})
+
+	t.Run("should not cache the failed calls", func(t *testing.T) {
+		doCall(t, http.MethodGet, "/go/api/admin/pipelines/missing", "Bearer token-1")
+		doCall(t, http.MethodGet, "/go/api/admin/pipelines/missing", "Bearer token-1")
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("should clear the cache when a call modifies the resources", func(t *testing.T) {
+		assert.JSONEq(t, `{"call": 5}`, doCall(t, http.MethodPut, "/go/api/admin/pipelines/sample", "Bearer token-1"))
+		assert.JSONEq(t, `{"call": 6}`, doCall(t, http.MethodGet, "/go/api/admin/pipelines/sample", "Bearer token-1"))
+	})
+
+	t.Run("should bypass the cache for the GET calls asking for no-cache, and refresh it", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/go/api/admin/pipelines/sample", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token-1")
+		req.Header.Set("Cache-Control", "no-cache")
+
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"call": 7}`, string(body))
+
+		assert.JSONEq(t, `{"call": 7}`, doCall(t, http.MethodGet, "/go/api/admin/pipelines/sample", "Bearer token-1"))
+	})
+}
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DryRunHeader is set on the responses returned for the calls withheld by DryRun, the responses are otherwise indistinguishable from the ones of GoCD server.
+const DryRunHeader = "X-Gocd-Cli-Dry-Run"
+
+// DryRun withholds the calls that could modify the resources on GoCD server, and prints them instead.
+// Calls that only read (GET, HEAD and OPTIONS) are still made, so that the commands could compute the diff against the server state.
+type DryRun struct {
+	out      io.Writer
+	mutex    sync.Mutex
+	withheld int
+}
+
+// NewDryRun returns DryRun that prints the withheld calls to the writer passed.
+func NewDryRun(out io.Writer) *DryRun {
+	return &DryRun{out: out}
+}
+
+// Middleware returns the Middleware that withholds the calls. The withheld calls are answered as succeeded, echoing the body sent
+// and the etag it was based on, so that the commands making more than one call carry on and every call they would make is printed.
+func (dryRun *DryRun) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next.RoundTrip(req)
+			}
+
+			requestBody, err := readBody(&req.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			dryRun.mutex.Lock()
+			defer dryRun.mutex.Unlock()
+
+			dryRun.withheld++
+
+			if _, err = fmt.Fprintf(dryRun.out, "dry run, request not sent: %s %s\n", req.Method, req.URL.String()); err != nil {
+				return nil, err
+			}
+
+			if len(requestBody) != 0 {
+				var indented bytes.Buffer
+				if json.Indent(&indented, requestBody, "", "  ") == nil {
+					requestBody = indented.Bytes()
+				}
+
+				if _, err = fmt.Fprintf(dryRun.out, "%s\n", requestBody); err != nil {
+					return nil, err
+				}
+			}
+
+			header := make(http.Header)
+			header.Set("Content-Type", "application/json")
+			header.Set(DryRunHeader, "withheld")
+
+			if eTag := req.Header.Get("If-Match"); len(eTag) != 0 {
+				header.Set("ETag", eTag)
+			}
+
+			responseBody := `{"message": "request withheld by dry run"}`
+			if json.Valid(requestBody) {
+				responseBody = string(requestBody)
+			}
+
+			return newResponse(req, RecordedResponse{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       responseBody,
+			}), nil
+		})
+	}
+}
+
+// Withheld returns the number of calls withheld since it was last called, and resets the count.
+func (dryRun *DryRun) Withheld() int {
+	dryRun.mutex.Lock()
+	defer dryRun.mutex.Unlock()
+
+	withheld := dryRun.withheld
+	dryRun.withheld = 0
+
+	return withheld
+}
@@ -0,0 +1,79 @@
+package transport_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		calls++
+		_, _ = io.WriteString(writer, `{"name": "sample"}`)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+
+	dryRun := transport.NewDryRun(&out)
+
+	base, err := transport.NewTransport(transport.Config{})
+	require.NoError(t, err)
+
+	gateway, err := transport.NewGateway(server.URL+"/go", transport.Chain(base, dryRun.Middleware()))
+	require.NoError(t, err)
+	defer gateway.Close()
+
+	doCall := func(t *testing.T, method, body string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequest(method, gateway.URL()+"/api/admin/pipelines/sample", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("If-Match", `"etag"`)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { resp.Body.Close() })
+
+		return resp
+	}
+
+	t.Run("should make the calls that only read from the server", func(t *testing.T) {
+		resp := doCall(t, http.MethodGet, "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(transport.DryRunHeader))
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, out.String())
+		assert.Equal(t, 0, dryRun.Withheld())
+	})
+
+	t.Run("should withhold the calls that modify the server, answer them as succeeded and print them", func(t *testing.T) {
+		resp := doCall(t, http.MethodPut, `{"name":"sample"}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get(transport.DryRunHeader))
+		assert.Equal(t, `"etag"`, resp.Header.Get("ETag"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "sample"}`, string(body))
+
+		assert.Equal(t, http.StatusOK, doCall(t, http.MethodDelete, "").StatusCode)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 2, dryRun.Withheld())
+		assert.Equal(t, 0, dryRun.Withheld())
+
+		expected := "dry run, request not sent: PUT " + server.URL + "/go/api/admin/pipelines/sample\n" +
+			"{\n  \"name\": \"sample\"\n}\n" +
+			"dry run, request not sent: DELETE " + server.URL + "/go/api/admin/pipelines/sample\n"
+		assert.Equal(t, expected, out.String())
+	})
+}
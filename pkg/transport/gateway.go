@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	gatewayPrefixLength = 16
+	readHeaderTimeout   = 30 * time.Second
+)
+
+// Gateway is a reverse proxy listening on loopback, that forwards the calls to GoCD server using a custom http.RoundTripper.
+// GoCD sdk client builds its own transport, pointing the client at the Gateway lets the cli control how the calls are made,
+// ex: mutual TLS, proxies, recording and caching.
+// The Gateway is served under a random path prefix, so that it is not usable by anyone who doesn't know it.
+type Gateway struct {
+	url      string
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewGateway starts the Gateway that forwards the calls to the target GoCD server using the transport passed.
+func NewGateway(target string, transport http.RoundTripper) (*Gateway, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixBytes := make([]byte, gatewayPrefixLength)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return nil, err
+	}
+
+	prefix := "/" + hex.EncodeToString(prefixBytes)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(proxyReq *httputil.ProxyRequest) {
+			proxyReq.Out.URL.Scheme = targetURL.Scheme
+			proxyReq.Out.URL.Host = targetURL.Host
+			proxyReq.Out.URL.Path = strings.TrimPrefix(proxyReq.In.URL.Path, prefix)
+			proxyReq.Out.URL.RawPath = ""
+			proxyReq.Out.Host = targetURL.Host
+		},
+		Transport: transport,
+		ErrorHandler: func(writer http.ResponseWriter, _ *http.Request, err error) {
+			http.Error(writer, fmt.Sprintf("calling GoCD server '%s' errored with: %v", targetURL.Host, err), http.StatusBadGateway)
+		},
+	}
+
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, prefix+"/") {
+			http.NotFound(writer, req)
+
+			return
+		}
+
+		proxy.ServeHTTP(writer, req)
+	})
+
+	gateway := &Gateway{
+		url:      "http://" + listener.Addr().String() + prefix + strings.TrimSuffix(targetURL.Path, "/"),
+		server:   &http.Server{Handler: handler, ReadHeaderTimeout: readHeaderTimeout},
+		listener: listener,
+	}
+
+	go func() {
+		// Serve returns only when the Gateway is closed or the listener fails, the calls to the Gateway would fail in both the cases.
+		_ = gateway.server.Serve(listener)
+	}()
+
+	return gateway, nil
+}
+
+// URL returns the base URL of the Gateway, which should be used by GoCD sdk client in place of the GoCD server URL.
+func (gateway *Gateway) URL() string {
+	return gateway.url
+}
+
+// Close stops the Gateway.
+func (gateway *Gateway) Close() error {
+	return gateway.server.Close()
+}
@@ -0,0 +1,226 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+)
+
+const (
+	fixtureFilePermission = 0o600
+	fixtureDirPermission  = 0o755
+	fixtureExtension      = ".json"
+)
+
+var (
+	unsafeFixtureChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+	redactedHeaders    = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+)
+
+// Exchange is a single HTTP call made to GoCD server, as saved in the fixture files.
+type Exchange struct {
+	Request  RecordedRequest  `json:"request" yaml:"request"`
+	Response RecordedResponse `json:"response" yaml:"response"`
+}
+
+// RecordedRequest holds the request part of the Exchange.
+type RecordedRequest struct {
+	Method string      `json:"method" yaml:"method"`
+	URL    string      `json:"url" yaml:"url"`
+	Header http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body   string      `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// RecordedResponse holds the response part of the Exchange.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code" yaml:"status_code"`
+	Header     http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// Recorder returns a Middleware that saves every call made to GoCD server as a fixture file under the directory passed.
+// Credentials carrying headers and the secrets in the bodies are redacted before saving, so that the fixtures could be shared in bug reports.
+func Recorder(dir string) (Middleware, error) {
+	if err := os.MkdirAll(dir, fixtureDirPermission); err != nil {
+		return nil, err
+	}
+
+	existing, err := filepath.Glob(filepath.Join(dir, "*"+fixtureExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	var mutex sync.Mutex
+
+	sequence := len(existing)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requestBody, err := readBody(&req.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+
+			responseBody, err := readBody(&resp.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			exchange := Exchange{
+				Request: RecordedRequest{
+					Method: req.Method,
+					URL:    req.URL.RequestURI(),
+					Header: redactHeader(req.Header),
+					Body:   string(redactBody(req.URL.Path, requestBody)),
+				},
+				Response: RecordedResponse{
+					StatusCode: resp.StatusCode,
+					Header:     redactHeader(resp.Header),
+					Body:       string(redactBody(req.URL.Path, responseBody)),
+				},
+			}
+
+			mutex.Lock()
+			sequence++
+			fixtureFile := filepath.Join(dir, fmt.Sprintf("%04d-%s-%s%s", sequence, req.Method,
+				strings.Trim(unsafeFixtureChars.ReplaceAllString(req.URL.Path, "_"), "_"), fixtureExtension))
+			mutex.Unlock()
+
+			data, err := json.MarshalIndent(exchange, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+
+			if err = os.WriteFile(fixtureFile, data, fixtureFilePermission); err != nil {
+				return nil, err
+			}
+
+			return resp, nil
+		})
+	}, nil
+}
+
+// Replayer returns a http.RoundTripper that serves the calls from the fixture files saved by Recorder, instead of calling GoCD server.
+// Calls are matched by method, URL and body, the body redacted as it was while recording. When the same call was recorded more than once, recordings are served in order
+// and the last one is repeated once they are exhausted, so that commands running with --watch still work.
+func Replayer(dir string) (http.RoundTripper, error) {
+	fixtureFiles, err := filepath.Glob(filepath.Join(dir, "*"+fixtureExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fixtureFiles) == 0 {
+		return nil, &errors.CLIError{Message: fmt.Sprintf("no recorded fixtures found under '%s'", dir)}
+	}
+
+	sort.Strings(fixtureFiles)
+
+	exchanges := make(map[string][]Exchange)
+
+	for _, fixtureFile := range fixtureFiles {
+		data, err := os.ReadFile(fixtureFile)
+		if err != nil {
+			return nil, err
+		}
+
+		var exchange Exchange
+		if err = json.Unmarshal(data, &exchange); err != nil {
+			return nil, &errors.CLIError{Message: fmt.Sprintf("reading fixture '%s' errored with '%v'", fixtureFile, err)}
+		}
+
+		key := exchangeKey(exchange.Request.Method, exchange.Request.URL, exchange.Request.Body)
+		exchanges[key] = append(exchanges[key], exchange)
+	}
+
+	var mutex sync.Mutex
+
+	served := make(map[string]int)
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requestBody, err := readBody(&req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		key := exchangeKey(req.Method, req.URL.RequestURI(), string(redactBody(req.URL.Path, requestBody)))
+
+		mutex.Lock()
+		recorded, ok := exchanges[key]
+		index := served[key]
+		if ok && index < len(recorded)-1 {
+			served[key]++
+		}
+		mutex.Unlock()
+
+		if !ok {
+			return nil, &errors.CLIError{Message: fmt.Sprintf("no recorded exchange found for '%s %s' under '%s'", req.Method, req.URL.RequestURI(), dir)}
+		}
+
+		return newResponse(req, recorded[index].Response), nil
+	}), nil
+}
+
+// newResponse builds the http.Response for the request from the RecordedResponse.
+func newResponse(req *http.Request, recorded RecordedResponse) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
+
+func exchangeKey(method, url, body string) string {
+	return strings.Join([]string{method, url, body}, "\x00")
+}
+
+// readBody reads the body completely and replaces it with a fresh reader, so that it can still be read by others.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = (*body).Close(); err != nil {
+		return nil, err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if len(redacted.Values(name)) != 0 {
+			redacted.Set(name, "REDACTED")
+		}
+	}
+
+	return redacted
+}
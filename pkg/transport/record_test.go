@@ -0,0 +1,169 @@
+package transport_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorderAndReplayer(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		calls++
+		body, _ := io.ReadAll(req.Body)
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(writer, `{"path": "`+req.URL.Path+`", "body": "`+string(body)+`", "call": `+string(rune('0'+calls))+`}`)
+	}))
+	defer server.Close()
+
+	fixtureDir := filepath.Join(t.TempDir(), "fixtures")
+
+	doCall := func(t *testing.T, roundTripper http.RoundTripper, method, body string) (int, string) {
+		t.Helper()
+
+		gateway, err := transport.NewGateway(server.URL+"/go", roundTripper)
+		require.NoError(t, err)
+		defer gateway.Close()
+
+		req, err := http.NewRequest(method, gateway.URL()+"/api/admin/pipelines/sample", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(respBody)
+	}
+
+	t.Run("should record the calls made to the server without credentials", func(t *testing.T) {
+		recorder, err := transport.Recorder(fixtureDir)
+		require.NoError(t, err)
+
+		base, err := transport.NewTransport(transport.Config{})
+		require.NoError(t, err)
+
+		roundTripper := transport.Chain(base, recorder)
+
+		_, body := doCall(t, roundTripper, http.MethodGet, "")
+		assert.JSONEq(t, `{"path": "/go/api/admin/pipelines/sample", "body": "", "call": 1}`, body)
+
+		_, body = doCall(t, roundTripper, http.MethodGet, "")
+		assert.JSONEq(t, `{"path": "/go/api/admin/pipelines/sample", "body": "", "call": 2}`, body)
+
+		_, body = doCall(t, roundTripper, http.MethodPut, "updated")
+		assert.JSONEq(t, `{"path": "/go/api/admin/pipelines/sample", "body": "updated", "call": 3}`, body)
+
+		fixtures, err := filepath.Glob(filepath.Join(fixtureDir, "*.json"))
+		require.NoError(t, err)
+		require.Len(t, fixtures, 3)
+		assert.Equal(t, "0001-GET-go_api_admin_pipelines_sample.json", filepath.Base(fixtures[0]))
+
+		fixture, err := os.ReadFile(fixtures[0])
+		require.NoError(t, err)
+		assert.NotContains(t, string(fixture), "YWRtaW46YWRtaW4=")
+	})
+
+	t.Run("should replay the recorded calls in order without calling the server", func(t *testing.T) {
+		callsBefore := calls
+
+		replayer, err := transport.Replayer(fixtureDir)
+		require.NoError(t, err)
+
+		_, body := doCall(t, replayer, http.MethodGet, "")
+		assert.JSONEq(t, `{"path": "/go/api/admin/pipelines/sample", "body": "", "call": 1}`, body)
+
+		_, body = doCall(t, replayer, http.MethodGet, "")
+		assert.JSONEq(t, `{"path": "/go/api/admin/pipelines/sample", "body": "", "call": 2}`, body)
+
+		_, body = doCall(t, replayer, http.MethodGet, "")
+		assert.JSONEq(t, `{"path": "/go/api/admin/pipelines/sample", "body": "", "call": 2}`, body)
+
+		_, body = doCall(t, replayer, http.MethodPut, "updated")
+		assert.JSONEq(t, `{"path": "/go/api/admin/pipelines/sample", "body": "updated", "call": 3}`, body)
+
+		status, _ := doCall(t, replayer, http.MethodDelete, "")
+		assert.Equal(t, http.StatusBadGateway, status)
+
+		assert.Equal(t, callsBefore, calls)
+	})
+}
+
+func TestRecorder_RedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(req.URL.Path, "/encrypt") {
+			_, _ = io.WriteString(writer, `{"encrypted_value": "AES:encrypted-secret"}`)
+
+			return
+		}
+
+		_, _ = io.Copy(writer, req.Body)
+	}))
+	defer server.Close()
+
+	fixtureDir := t.TempDir()
+
+	doCall := func(t *testing.T, roundTripper http.RoundTripper, path, body string) string {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/go"+path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		resp, err := roundTripper.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(respBody)
+	}
+
+	storeBody := `{"id": "docker", "properties": [{"key": "Username", "value": "gocd"}, {"key": "Password", "value": "plain-password"}, ` +
+		`{"key": "Token", "encrypted_value": "AES:encrypted-token"}], "environment_variables": [{"name": "DB", "secure": true, "value": "db-secret"}]}`
+
+	recorder, err := transport.Recorder(fixtureDir)
+	require.NoError(t, err)
+
+	base, err := transport.NewTransport(transport.Config{})
+	require.NoError(t, err)
+
+	roundTripper := transport.Chain(base, recorder)
+
+	assert.JSONEq(t, storeBody, doCall(t, roundTripper, "/api/admin/artifact_stores/docker", storeBody))
+	doCall(t, roundTripper, "/api/admin/encrypt", `{"value": "plain-text-secret"}`)
+
+	fixtures, err := filepath.Glob(filepath.Join(fixtureDir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, fixtures, 2)
+
+	for _, fixture := range fixtures {
+		data, err := os.ReadFile(fixture)
+		require.NoError(t, err)
+
+		for _, secret := range []string{"plain-password", "encrypted-token", "db-secret", "plain-text-secret", "encrypted-secret"} {
+			assert.NotContains(t, string(data), secret)
+		}
+	}
+
+	data, err := os.ReadFile(fixtures[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "gocd")
+
+	replayer, err := transport.Replayer(fixtureDir)
+	require.NoError(t, err)
+
+	assert.Contains(t, doCall(t, replayer, "/api/admin/artifact_stores/docker", storeBody), "REDACTED")
+}
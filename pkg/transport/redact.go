@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+var (
+	// redactedPaths are the endpoints whose bodies are redacted entirely, ex: the plain text passed to be encrypted.
+	redactedPaths = []string{"/api/admin/encrypt"}
+	// redactedFields are the fields of the JSON bodies that hold secrets, either in plain text or encrypted.
+	redactedFields = map[string]bool{
+		"password":           true,
+		"encrypted_password": true,
+		"encrypted_value":    true,
+		"bearer_token":       true,
+		"access_token":       true,
+		"token":              true,
+		"secret":             true,
+		"secret_key":         true,
+		"private_key":        true,
+		"api_key":            true,
+	}
+	// secretKeys matches the keys of the properties whose values are secrets, ex: the Password of an artifact store.
+	secretKeys = regexp.MustCompile(`(?i)password|secret|token|private.?key|api.?key|credential`)
+)
+
+// redactBody returns the body with the secrets it holds redacted, the calls made to redactedPaths are redacted entirely.
+// The body is returned as it is when it holds no secrets, so that the same redaction applied on replay still matches the recorded ones.
+func redactBody(path string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	for _, redactedPath := range redactedPaths {
+		if strings.HasSuffix(strings.TrimSuffix(path, "/"), redactedPath) {
+			return []byte(redacted)
+		}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+
+	if !redactValue(value) {
+		return body
+	}
+
+	redactedBody, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+
+	return redactedBody
+}
+
+// redactValue redacts the secrets in the JSON value in place, and returns true when any of them were redacted.
+// Besides the redactedFields, the values of the properties that are secure or whose keys match secretKeys are redacted.
+func redactValue(value interface{}) bool {
+	var changed bool
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		secure, _ := typedValue["secure"].(bool)
+		if key, ok := typedValue["key"].(string); ok && secretKeys.MatchString(key) {
+			secure = true
+		}
+
+		for field, fieldValue := range typedValue {
+			if redactedFields[strings.ToLower(field)] || (secure && field == "value") {
+				if text, ok := fieldValue.(string); ok && len(text) != 0 && text != redacted {
+					typedValue[field] = redacted
+					changed = true
+				}
+
+				continue
+			}
+
+			if redactValue(fieldValue) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range typedValue {
+			if redactValue(item) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Config holds the settings used for building the transport, that talks to GoCD server.
+type Config struct {
+	CaPath             string `json:"ca_path,omitempty" yaml:"ca_path,omitempty"`
+	ClientCertPath     string `json:"client_cert_path,omitempty" yaml:"client_cert_path,omitempty"`
+	ClientKeyPath      string `json:"client_key_path,omitempty" yaml:"client_key_path,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+	ProxyURL           string `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`
+	NoProxy            string `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty"`
+}
+
+// Middleware wraps a http.RoundTripper, to add behaviour around the calls made to GoCD server.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc lets an ordinary function be used as http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (fn RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// IsCustom returns true if the Config needs a transport other than what GoCD sdk builds by itself.
+func (cfg Config) IsCustom() bool {
+	return len(cfg.ClientCertPath) != 0 || len(cfg.ClientKeyPath) != 0 || cfg.InsecureSkipVerify || len(cfg.ProxyURL) != 0 || len(cfg.NoProxy) != 0
+}
+
+// NewTransport returns the base http.RoundTripper built as per the Config.
+func NewTransport(cfg Config) (http.RoundTripper, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec
+	}
+
+	if len(cfg.CaPath) != 0 {
+		caContent, err := os.ReadFile(cfg.CaPath)
+		if err != nil {
+			return nil, err
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caContent) {
+			return nil, &errors.CLIError{Message: fmt.Sprintf("no valid certificates found in CA file '%s'", cfg.CaPath)}
+		}
+
+		tlsConfig.RootCAs = certPool
+	}
+
+	if len(cfg.ClientCertPath) != 0 || len(cfg.ClientKeyPath) != 0 {
+		if len(cfg.ClientCertPath) == 0 || len(cfg.ClientKeyPath) == 0 {
+			return nil, &errors.CLIError{Message: "both client certificate and key should be set for mutual TLS"}
+		}
+
+		certificate, err := tls.LoadX509KeyPair(cfg.ClientCertPath, cfg.ClientKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, &errors.CLIError{Message: "default http transport is not of type *http.Transport"}
+	}
+
+	transport = transport.Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = getProxyFunc(cfg)
+
+	return transport, nil
+}
+
+// Chain wraps the base http.RoundTripper with the middlewares, the first middleware being the outermost.
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	for index := len(middlewares) - 1; index >= 0; index-- {
+		base = middlewares[index](base)
+	}
+
+	return base
+}
+
+func getProxyFunc(cfg Config) func(*http.Request) (*url.URL, error) {
+	if len(cfg.ProxyURL) == 0 && len(cfg.NoProxy) == 0 {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyConfig := httpproxy.FromEnvironment()
+	if len(cfg.ProxyURL) != 0 {
+		proxyConfig.HTTPProxy = cfg.ProxyURL
+		proxyConfig.HTTPSProxy = cfg.ProxyURL
+	}
+
+	if len(cfg.NoProxy) != 0 {
+		proxyConfig.NoProxy = cfg.NoProxy
+	}
+
+	proxyFunc := proxyConfig.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
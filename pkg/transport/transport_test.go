@@ -0,0 +1,87 @@
+package transport_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGateway(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(writer, req.URL.Path+" "+req.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	t.Run("should forward the calls to the server through the gateway", func(t *testing.T) {
+		base, err := transport.NewTransport(transport.Config{InsecureSkipVerify: true})
+		require.NoError(t, err)
+
+		var calls []string
+		roundTripper := transport.Chain(base, func(next http.RoundTripper) http.RoundTripper {
+			return transport.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, req.Method+" "+req.URL.Path)
+
+				return next.RoundTrip(req)
+			})
+		})
+
+		gateway, err := transport.NewGateway(server.URL+"/go", roundTripper)
+		require.NoError(t, err)
+		defer gateway.Close()
+
+		req, err := http.NewRequest(http.MethodGet, gateway.URL()+"/api/version", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "/go/api/version Bearer token", string(body))
+		assert.Equal(t, []string{"GET /go/api/version"}, calls)
+	})
+
+	t.Run("should not forward the calls made without the gateway prefix", func(t *testing.T) {
+		base, err := transport.NewTransport(transport.Config{InsecureSkipVerify: true})
+		require.NoError(t, err)
+
+		gateway, err := transport.NewGateway(server.URL+"/go", base)
+		require.NoError(t, err)
+		defer gateway.Close()
+
+		gatewayURL := gateway.URL()
+		resp, err := http.Get(gatewayURL[:strings.LastIndex(gatewayURL[:len(gatewayURL)-len("/go")], "/")] + "/go/api/version")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should fail verifying the server certificate when CA is not trusted", func(t *testing.T) {
+		base, err := transport.NewTransport(transport.Config{})
+		require.NoError(t, err)
+
+		gateway, err := transport.NewGateway(server.URL+"/go", base)
+		require.NoError(t, err)
+		defer gateway.Close()
+
+		resp, err := http.Get(gateway.URL() + "/api/version")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	})
+}
+
+func TestNewTransport(t *testing.T) {
+	t.Run("should error when only one of client certificate and key is set", func(t *testing.T) {
+		_, err := transport.NewTransport(transport.Config{ClientCertPath: "client.crt"})
+		require.EqualError(t, err, "both client certificate and key should be set for mutual TLS")
+	})
+}
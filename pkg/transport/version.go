@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/cache"
+)
+
+// Versioner returns a Middleware that saves the resources fetched from GoCD server in the cache passed, keyed by their ETag.
+// The version a change was based on is looked up by VersionKey, to merge the change with the ones made to the resource since.
+func Versioner(versions *cache.Cache) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+				return resp, err
+			}
+
+			eTag := resp.Header.Get("ETag")
+			if len(eTag) == 0 {
+				return resp, nil
+			}
+
+			responseBody, err := readBody(&resp.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			if !json.Valid(responseBody) {
+				return resp, nil
+			}
+
+			// failing to save the version should not fail the call, the conflicting updates would then not be merged.
+			_ = versions.Set(VersionKey(eTag), json.RawMessage(responseBody))
+
+			return resp, nil
+		})
+	}
+}
+
+// VersionKey returns the key the version of a resource with the ETag passed is saved against, the weak and quoted forms are treated alike.
+func VersionKey(eTag string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimSpace(eTag), "W/"), `"`)
+}
@@ -0,0 +1,68 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/gocd-cli/pkg/cache"
+	"github.com/nikhilsbhat/gocd-cli/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersioner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/go/api/admin/environments/production":
+			writer.Header().Set("ETag", `W/"etag-1"`)
+			_, _ = io.WriteString(writer, `{"name": "production"}`)
+		case "/go/api/admin/environments/staging":
+			_, _ = io.WriteString(writer, `{"name": "staging"}`)
+		default:
+			http.NotFound(writer, req)
+		}
+	}))
+	defer server.Close()
+
+	versions := cache.New(t.TempDir(), time.Minute)
+	httpClient := &http.Client{Transport: transport.Chain(http.DefaultTransport, transport.Versioner(versions))}
+
+	doCall := func(t *testing.T, path string) string {
+		t.Helper()
+
+		resp, err := httpClient.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	t.Run("should save the resource fetched against its ETag", func(t *testing.T) {
+		assert.JSONEq(t, `{"name": "production"}`, doCall(t, "/go/api/admin/environments/production"))
+
+		var version json.RawMessage
+		require.True(t, versions.Get(transport.VersionKey(`"etag-1"`), &version))
+		assert.JSONEq(t, `{"name": "production"}`, string(version))
+	})
+
+	t.Run("should not save the resources fetched without an ETag", func(t *testing.T) {
+		assert.JSONEq(t, `{"name": "staging"}`, doCall(t, "/go/api/admin/environments/staging"))
+		doCall(t, "/go/api/admin/environments/missing")
+
+		var version json.RawMessage
+		assert.False(t, versions.Get(transport.VersionKey(""), &version))
+	})
+}
+
+func TestVersionKey(t *testing.T) {
+	assert.Equal(t, "etag-1", transport.VersionKey(`W/"etag-1"`))
+	assert.Equal(t, "etag-1", transport.VersionKey(`"etag-1"`))
+	assert.Equal(t, "etag-1", transport.VersionKey("etag-1"))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/thoas/go-funk"
+	"golang.org/x/term"
 )
 
 // ReadConfig holds the necessary inputs that is required by Reader.
@@ -71,6 +72,31 @@ func (cfg *ReadConfig) Reader() (bool, Options) {
 	}
 }
 
+// SecretReader reads a secret such as passphrase from the shell input, without echoing it back when stdin is a terminal.
+func (cfg *ReadConfig) SecretReader() (string, error) {
+	fmt.Printf("$%s>> ", cfg.ShellName)
+	fmt.Printf("%s: ", cfg.ShellMessage)
+
+	stdinFd := int(os.Stdin.Fd()) //nolint:gosec
+	if term.IsTerminal(stdinFd) {
+		secret, err := term.ReadPassword(stdinFd)
+		fmt.Println()
+
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	secret, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && len(secret) == 0 {
+		return "", err
+	}
+
+	return strings.TrimSpace(secret), nil
+}
+
 // Contains checks if user passed input is part of predefined Options.
 // If yes returns true else returns false.
 func (inputOptions Option) Contains(input string) (bool, Options) {